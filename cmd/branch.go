@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch [name]",
+	Short: "Lint the name of a Git branch.",
+	Long: `Lint the name of a Git branch.
+
+Use this command to enforce branch names mirroring the commit types, e.g.
+"feat/parser-bang-support" or "fix/ABC-123-stdin". The name of the current branch
+is linted if no name is provided (nothing is linted if HEAD is detached, e.g.
+during a rebase). The allowed patterns and the exempted branches (like "main") are
+configured with the "branch.patterns" and "branch.exempt" settings of the
+.crisp.json file, run "crisp explain branch-name" for the details.`,
+	Example: `crisp branch
+crisp branch feat/parser-bang-support`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		} else {
+			// There is nothing to lint when HEAD is detached, e.g. during a rebase or a
+			// bisection
+			repo := git.NewRepo("")
+			var err error
+			if name, err = repo.CurrentBranch(); err != nil && repo.HasCommit("HEAD") {
+				cmd.Println("no branch is checked out, skipping")
+				return
+			}
+			if err != nil {
+				cmd.PrintErrln("error: no branch is checked out, pass its name instead")
+				os.Exit(1)
+			}
+		}
+
+		v := validator.NewValidatorWithConfig(loadConfig(cmd))
+		diagnostics, err := v.LintBranch(name)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+
+		if !reportDiagnostics(cmd, diagnostics) {
+			os.Exit(1)
+		}
+
+		cmd.Println("valid branch name")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/ci"
+	"github.com/Weburz/crisp/internal/git"
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Lint the commits of the current CI build.",
+	Long: `Lint the commits of the current CI build.
+
+Use this command in a CI pipeline to lint the messages of every commit introduced
+by the pull request or push being built, without computing the range of commits by
+hand. The CI service is detected from its environment variables (and event payload)
+and the following services are supported:
+
+  GitHub Actions (and the compatible Gitea and Forgejo Actions), GitLab CI,
+  Bitbucket Pipelines, Jenkins, Azure Pipelines and Buildkite
+
+When the base commit of the build is not known, the range starts where the commits
+were forked from the target branch (which must be fetched). If neither are known,
+only the head commit is linted. Shallow clones must contain enough history for the
+range to be computed.`,
+	Example: `# A GitHub Actions step, after a checkout with "fetch-depth: 0"
+- run: crisp ci`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		build, err := ci.Detect(os.Getenv)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+
+		event := "push"
+		if build.PullRequest {
+			event = "pull request"
+		}
+		cmd.PrintErrf("detected a %s build on %s\n", event, build.Provider)
+		if !build.HasBase() {
+			cmd.PrintErrln("warning: the base of the build is not known, " +
+				"linting the head commit only")
+		}
+
+		repo := git.NewRepo("")
+		shas, err := build.Commits(repo)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+
+		ok, err := lintCommits(cmd, repo, shas)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+
+		cmd.Printf("%d commit(s) have valid commit messages\n", len(shas))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit [flags] [-- <git commit args>...]",
+	Short: "Build, lint and record a commit non-interactively.",
+	Long: `Build, lint and record a commit non-interactively.
+
+Use this command to construct a Conventional Commits message from its individual
+parts instead of concatenating strings by hand. The message is serialised in its
+canonical form and validated before "git commit" is invoked. Any arguments passed
+after "--" are forwarded to "git commit" as is.`,
+	Example: `crisp commit --type fix --scope reader --description "handle empty stdin"
+crisp commit -t feat -d "add new flag" --trailer Refs=#12 --dry-run
+crisp commit -t fix -d "drop legacy api" --breaking "legacy api removed" -- --amend`,
+	Run: func(cmd *cobra.Command, args []string) {
+		typ, _ := cmd.Flags().GetString("type")
+		scope, _ := cmd.Flags().GetString("scope")
+		desc, _ := cmd.Flags().GetString("description")
+		bodyFile, _ := cmd.Flags().GetString("body-file")
+		breaking, _ := cmd.Flags().GetString("breaking")
+		trailers, _ := cmd.Flags().GetStringArray("trailer")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		msg := &parser.CommitMessage{
+			Type:        typ,
+			Scope:       scope,
+			Description: desc,
+			Footers:     map[string]string{},
+		}
+
+		// Read the commit message body from a file (or STDIN if the path is "-")
+		if bodyFile != "" {
+			body, err := readInputFile(bodyFile)
+			if err != nil {
+				cmd.PrintErrf("error reading body file: %s\n", err)
+				os.Exit(1)
+			}
+			msg.Body = strings.TrimSpace(body)
+		}
+
+		if breaking != "" {
+			msg.Footers["BREAKING CHANGE"] = breaking
+		}
+
+		for _, trailer := range trailers {
+			key, val, err := parseTrailerFlag(trailer)
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+			if prev, ok := msg.Footers[key]; ok {
+				val = prev + ", " + val
+			}
+			msg.Footers[key] = val
+		}
+
+		// Parse the serialised message again so the exact text handed over to Git is
+		// what gets validated
+		message := msg.String()
+		v := validator.NewValidatorWithConfig(loadConfig(cmd))
+		p, err := parser.ParseCommitMessage(message)
+		if err != nil {
+			cmd.PrintErrf("%s\n", v.Redact(err.Error()))
+			os.Exit(1)
+		}
+
+		diagnostics := v.LintWithPaths(p, stagedFiles())
+		if !reportDiagnostics(cmd, diagnostics) {
+			os.Exit(1)
+		}
+
+		if dryRun {
+			cmd.Println(message)
+			return
+		}
+
+		if err := git.NewRepo("").Commit(message, args...); err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// parseTrailerFlag splits a "--trailer" flag value of the form "<KEY>=<VALUE>" (or
+// "<KEY>: <VALUE>") into its key and value. An error is returned if the key is not a
+// footer recognised by the parser since it would silently end up in the body.
+func parseTrailerFlag(s string) (string, string, error) {
+	key, val, ok := strings.Cut(s, "=")
+	if !ok {
+		key, val, ok = strings.Cut(s, ":")
+	}
+	key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+
+	if !ok || key == "" || val == "" {
+		return "", "", fmt.Errorf("invalid trailer %q, expected <KEY>=<VALUE>", s)
+	}
+
+	if !parser.IsKnownFooter(key) {
+		return "", "", fmt.Errorf("unknown trailer key %q", key)
+	}
+
+	return key, val, nil
+}
+
+func init() {
+	commitCmd.Flags().StringP("type", "t", "", "Type of the commit (e.g. feat, fix)")
+	commitCmd.Flags().StringP("scope", "S", "", "Optional scope of the commit")
+	commitCmd.Flags().
+		StringP("description", "d", "", "Short description of the change")
+	commitCmd.Flags().
+		String("body-file", "", "Read the message body from a file (\"-\" for STDIN)")
+	commitCmd.Flags().
+		String("breaking", "", "Describe a breaking change in a BREAKING CHANGE footer")
+	commitCmd.Flags().
+		StringArray("trailer", nil, "Add a footer as <KEY>=<VALUE> (can be repeated)")
+	commitCmd.Flags().
+		Bool("dry-run", false, "Print the commit message instead of committing")
+
+	_ = commitCmd.MarkFlagRequired("type")
+	_ = commitCmd.MarkFlagRequired("description")
+
+	rootCmd.AddCommand(commitCmd)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// lintCommits lints the messages of the given commits and prints the diagnostics of
+// every commit which violates any of the rules. Returns false if any of the commit
+// messages were rejected.
+func lintCommits(cmd *cobra.Command, repo *git.Repo, shas []string) (bool, error) {
+	cfg := loadConfig(cmd)
+	v := validator.NewValidatorWithConfig(cfg)
+	rejected := 0
+
+	for _, sha := range shas {
+		message, err := repo.Message(sha)
+		if err != nil {
+			return false, err
+		}
+
+		// Each commit is compared against its own changes
+		paths, err := repo.ChangedFiles(sha)
+		if err != nil {
+			return false, err
+		}
+
+		// The sign-off must match the author of the commit or the configured user
+		author := func() (string, error) { return repo.Author(sha) }
+		identities := knownIdentities(author, repo.User)
+
+		header, _, _ := strings.Cut(message, "\n")
+		diagnostics, ok := lintMessage(cfg, message, paths, identities)
+		if len(diagnostics) == 0 {
+			continue
+		}
+		if !ok {
+			rejected++
+		}
+
+		cmd.PrintErrf("commit %.12s: %s\n", sha, v.Redact(header))
+		for _, d := range diagnostics {
+			cmd.PrintErrf("%s\n", indentLines(d, "  "))
+		}
+	}
+
+	if rejected > 0 {
+		cmd.PrintErrf(
+			"\n%d of %d commit(s) have invalid commit messages\n"+
+				"info: run \"crisp explain <RULE>\" to learn more about a rule\n",
+			rejected,
+			len(shas),
+		)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// lintMessage parses and lints a commit message and returns the rendered diagnostics
+// (or the parse error) along with whether the message was accepted. The rules needing
+// the changed files are only run if paths is not nil, and the sign-off must match one
+// of the identities if any are given. The secrets quoted by the parse error are
+// redacted like they are in the diagnostics.
+func lintMessage(
+	cfg *config.Config,
+	message string,
+	paths []string,
+	identities []string,
+) ([]string, bool) {
+	v := validator.NewValidatorWithConfig(cfg)
+	p, err := parser.ParseCommitMessage(message)
+	if err != nil {
+		return []string{v.Redact(err.Error())}, false
+	}
+
+	v.SetIdentities(identities)
+	diagnostics := v.LintWithPaths(p, paths)
+	rendered := []string{}
+	for _, d := range diagnostics {
+		rendered = append(rendered, d.String())
+	}
+
+	return rendered, !validator.HasErrors(diagnostics)
+}
+
+// reportDiagnostics prints the diagnostics of a commit message. All of them are
+// printed as a *validator.ValidationError if any of them is an error, otherwise only
+// the warnings are printed. Returns false if the commit message was rejected.
+func reportDiagnostics(cmd *cobra.Command, diagnostics []validator.Diagnostic) bool {
+	if validator.HasErrors(diagnostics) {
+		cmd.PrintErrf("%s\n", &validator.ValidationError{Diagnostics: diagnostics})
+		return false
+	}
+
+	for _, d := range diagnostics {
+		cmd.PrintErrln(d)
+	}
+	return true
+}
+
+// stagedFiles returns the paths of the files changed by the commit being made, or nil
+// if they are not known (e.g. outside of a repository).
+func stagedFiles() []string {
+	paths, err := git.NewRepo("").StagedFiles()
+	if err != nil {
+		return nil
+	}
+	return paths
+}
+
+// knownIdentities returns the identities ("Name <email>") returned by the lookups,
+// skipping the ones which are not known (e.g. if "user.email" is not configured).
+func knownIdentities(lookups ...func() (string, error)) []string {
+	identities := []string{}
+	for _, lookup := range lookups {
+		if identity, err := lookup(); err == nil {
+			identities = append(identities, identity)
+		}
+	}
+	return identities
+}
+
+// hookIdentities returns the identities the sign-off of the commit being made may
+// match, i.e. its author and the configured user.
+func hookIdentities() []string {
+	repo := git.NewRepo("")
+	return knownIdentities(repo.NextAuthor, repo.User)
+}
+
+// indentLines prefixes every non-empty line of s with prefix.
+func indentLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		if line != "" {
+			lines[idx] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/git/gittest"
+)
+
+// token is a GitHub token which must never be printed as is.
+var token = "ghp_" + strings.Repeat("a1B2", 9)
+
+func TestLintCommits_RedactsHeader(t *testing.T) {
+	dir := gittest.NewRepo(t)
+	sha := gittest.Commit(t, dir, "fix: use "+token)
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&out)
+	ok, err := lintCommits(cmd, git.NewRepo(dir), []string{sha})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected the commit to be rejected")
+	}
+
+	want := "commit " + sha[:12] + ": fix: use ghp_****\n"
+	if !strings.HasPrefix(out.String(), want) || strings.Contains(out.String(), token) {
+		t.Errorf("expected the header to be redacted, got:\n%s", out.String())
+	}
+}
+
+func TestLintMessage_RedactsParseError(t *testing.T) {
+	diagnostics, ok := lintMessage(config.Default(), "use "+token, nil, nil)
+	if ok || len(diagnostics) != 1 {
+		t.Fatalf("expected the parse error, got %v", diagnostics)
+	}
+	if strings.Contains(diagnostics[0], token) {
+		t.Errorf("expected the parse error to be redacted, got %q", diagnostics[0])
+	}
+}
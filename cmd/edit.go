@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/editor"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <file>",
+	Short: "Edit a commit message until it passes the lint.",
+	Long: `Edit a commit message until it passes the lint.
+
+Use this command as Git's editor (through GIT_EDITOR or the "core.editor" setting)
+so that a rejected commit message is never thrown away. It launches your real
+editor, lints the message once the editor exits and, on failure, inserts the
+violations as comments at the top of the message before re-opening the editor.
+This repeats until the message passes the lint or is emptied to abort the commit.
+The other files Git opens in the editor (e.g. the todo list of an interactive
+rebase) are not linted.
+
+The real editor is taken from "--editor", or the CRISP_EDITOR, VISUAL or EDITOR
+environment variables (in that order), falling back to "vi".`,
+	Example: `git config core.editor "crisp edit"
+GIT_EDITOR="crisp edit --editor 'code --wait'" git commit`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		command, _ := cmd.Flags().GetString("editor")
+		if command == "" {
+			command = editor.DefaultCommand()
+		}
+
+		cfg, paths, identities := loadConfig(cmd), stagedFiles(), hookIdentities()
+		lint := func(message string) ([]string, bool) {
+			return lintMessage(cfg, message, paths, identities)
+		}
+
+		if err := editor.NewEditor(command, lint).Edit(args[0]); err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	editCmd.Flags().String("editor", "", "The editor to launch (e.g. \"code --wait\")")
+
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <RULE>",
+	Short: "Explain a validation rule in detail.",
+	Long: `Explain a validation rule in detail.
+
+Use this command to read the long-form documentation of a rule including the
+rationale behind it, examples of passing and failing commit messages and the
+options it accepts. Run "crisp rules" to list the IDs of all the rules.`,
+	Example: "crisp explain header-length",
+	Args:    cobra.ExactArgs(1),
+	ValidArgsFunction: func(
+		cmd *cobra.Command,
+		args []string,
+		toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		ids := []string{}
+		for _, r := range validator.Rules() {
+			ids = append(ids, r.ID)
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		r, ok := validator.LookupRule(args[0])
+		if !ok {
+			cmd.PrintErrf(
+				"error: unknown rule %q, run \"crisp rules\" to list them\n",
+				args[0],
+			)
+			os.Exit(1)
+		}
+
+		cmd.Print(r.Explain())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
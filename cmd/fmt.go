@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/formatter"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file]",
+	Short: "Format a Git commit message canonically.",
+	Long: `Format a Git commit message canonically.
+
+Use this command to parse a commit message and re-emit it in its canonical form.
+The header, body and footers are separated by a single blank line, the body
+paragraphs are wrapped at the configured width (leaving code blocks and lists
+untouched), footers are spelled and ordered consistently and trailing whitespaces
+are stripped. Other trailers (e.g. "Signed-off-by") are kept as they are, and so
+are the comment lines Git adds at the end of the message. The message is read
+from the given file or from STDIN if the file is omitted or "-".`,
+	Example: `crisp fmt .git/COMMIT_EDITMSG
+crisp fmt --write .git/COMMIT_EDITMSG
+git log -1 --format=%B | crisp fmt --check`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		width, _ := cmd.Flags().GetInt("width")
+		check, _ := cmd.Flags().GetBool("check")
+		write, _ := cmd.Flags().GetBool("write")
+
+		path := "-"
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		if write && path == "-" {
+			cmd.PrintErrln("error: --write requires a file to be provided")
+			os.Exit(1)
+		}
+
+		message, err := readInputFile(path)
+		if err != nil {
+			cmd.PrintErrf("error reading commit message: %s\n", err)
+			os.Exit(1)
+		}
+
+		formatted, err := formatter.NewFormatter(width).FormatMessage(message)
+		if err != nil {
+			cmd.PrintErrf("%s\n", err)
+			os.Exit(1)
+		}
+
+		switch {
+		case check:
+			if formatted != message {
+				cmd.PrintErrf("%s: commit message is not formatted canonically\n", path)
+				os.Exit(1)
+			}
+		case write:
+			if formatted == message {
+				return
+			}
+			if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+				cmd.PrintErrf("error writing commit message: %s\n", err)
+				os.Exit(1)
+			}
+		default:
+			cmd.Print(formatted)
+		}
+	},
+}
+
+func init() {
+	fmtCmd.Flags().
+		Int("width", formatter.DefaultWidth, "Wrap body paragraphs at this width")
+	fmtCmd.Flags().
+		Bool("check", false, "Fail if the message is not formatted canonically")
+	fmtCmd.Flags().
+		BoolP("write", "w", false, "Write the formatted message back to the file")
+
+	fmtCmd.MarkFlagsMutuallyExclusive("check", "write")
+
+	rootCmd.AddCommand(fmtCmd)
+}
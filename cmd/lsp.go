@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/lsp"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/version"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run the Language Server Protocol server over STDIO.",
+	Long: `Run the Language Server Protocol server over STDIO.
+
+Use this command to lint commit messages as they are typed in any editor with
+Language Server Protocol support (Neovim, VS Code, Helix and so on) for "gitcommit"
+buffers. The server publishes the diagnostics of the validator, offers code actions
+for the violations which can be fixed automatically, completes the types, scopes
+(collected from the recent history of the repository), footer and trailer keys
+(e.g. "Signed-off-by"), and shows the documentation of the rules on hover.`,
+	Example: `# Helix (languages.toml)
+[language-server.crisp]
+command = "crisp"
+args = ["lsp"]`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		server := lsp.NewServer(
+			cmd.InOrStdin(),
+			os.Stdout,
+			version.GetVersionInfo().Version,
+			historyScopes(git.NewRepo("")),
+		)
+
+		if err := server.Run(); err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// historyScopes returns the scopes used by the recent commits of the repository,
+// sorted alphabetically. It returns no scopes outside of a repository.
+func historyScopes(repo *git.Repo) []string {
+	subjects, err := repo.Subjects(1000)
+	if err != nil {
+		return []string{}
+	}
+
+	scopes := []string{}
+	for _, subject := range subjects {
+		p, err := parser.ParseCommitMessage(subject)
+		if err != nil || p.Scope == "" || slices.Contains(scopes, p.Scope) {
+			continue
+		}
+		scopes = append(scopes, p.Scope)
+	}
+	slices.Sort(scopes)
+
+	return scopes
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
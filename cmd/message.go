@@ -18,6 +18,13 @@
 Use this command to lint Git commit messages according to the Conventional
 Commit v1.0.0 specifications. To learn more about the specifications, refer to
 its the documentations here - https://www.conventionalcommits.org.
+
+With "--fix", the fixes of the errors (e.g. the casing of the type or a missing
+sign-off) are applied before linting the message again, and every applied fix is
+reported. The fixes of the warnings are only suggestions and never applied. The
+fixed message is printed, except when run as a commit-msg hook with "--stdin",
+where only a missing sign-off is added and the message is written back to
+.git/COMMIT_EDITMSG.
 `
 
 var messageCmd = &cobra.Command{
@@ -25,23 +32,32 @@
 	Aliases: []string{"msg"},
 	Short:   shortUsage,
 	Long:    longUsage,
-	Example: "crisp message \"chore: fix an annoying bug\"",
-	Args:    cobra.MaximumNArgs(1),
+	Example: `crisp message "chore: fix an annoying bug"
+crisp message --stdin --fix < .git/COMMIT_EDITMSG`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		useStdin, _ := cmd.Flags().GetBool("stdin")
+		fix, _ := cmd.Flags().GetBool("fix")
+		commitMsgFile := filepath.Join(".", ".git", "COMMIT_EDITMSG")
 		var message string
 		var err error
 
 		if useStdin {
-			r := reader.NewStdinReader()
-			message, err = r.Read()
-			if err != nil {
-				cmd.PrintErrf(
-					"warning: failed to read stdin: %s, reading .git/COMMIT_EDITMSG\n",
-					err,
-				)
-
-				commitMsgFile := filepath.Join(".", ".git", "COMMIT_EDITMSG")
+			// The fixes are written back to the commit message file, so all of it is
+			// read rather than the line piped to STDIN
+			if !fix {
+				r := reader.NewStdinReader()
+				message, err = r.Read()
+				if err != nil {
+					cmd.PrintErrf(
+						"warning: failed to read stdin: %s, reading %s\n",
+						err,
+						commitMsgFile,
+					)
+				}
+			}
+
+			if fix || err != nil {
 				r, err := reader.NewFileReader(commitMsgFile)
 				if err != nil {
 					cmd.PrintErrf("error reading commit message file: %s\n", err)
@@ -61,20 +77,63 @@
 			message = args[0]
 		}
 
+		// The files changed by the commit and its author are only relevant when run as
+		// a commit-msg hook, and so are the comments Git discards from the message
+		// along with everything below the scissors line (e.g. the diff added by "git
+		// commit --verbose")
+		v := validator.NewValidatorWithConfig(loadConfig(cmd))
+		var paths []string
+		raw := message
+		if useStdin {
+			paths = stagedFiles()
+			v.SetIdentities(hookIdentities())
+			message = parser.StripComments(message)
+		}
+
+		// Apply the fixes of the errors, writing them back to the commit message file
+		// (followed by the comments of Git) when run as a commit-msg hook. The hook
+		// only adds the missing sign-off, the other fixes rewriting what was written
+		if fix {
+			var only []string
+			if useStdin {
+				only = []string{"signed-off-by"}
+			}
+			fixed, applied := v.FixMessage(message, paths, only...)
+			for _, d := range applied {
+				cmd.PrintErrf("fixed[%s]: %s\n", d.Rule, d.Fix.Title)
+			}
+			if useStdin && fixed != message {
+				content := fixed + "\n"
+				if comments := parser.TrailingComments(raw); comments != "" {
+					content += "\n" + comments
+				}
+				err := os.WriteFile(commitMsgFile, []byte(content), 0o644)
+				if err != nil {
+					cmd.PrintErrf("error writing commit message file: %s\n", err)
+					os.Exit(1)
+				}
+			}
+			message = fixed
+		}
+
 		// Parse the commit message for further validation
 		p, err := parser.ParseCommitMessage(message)
 		if err != nil {
-			cmd.PrintErrf("%s\n", err)
+			cmd.PrintErrf("%s\n", v.Redact(err.Error()))
 			os.Exit(1)
 		}
 
 		// Validate the parsed commit message for apropriate stucture and format
-		if status, err := validator.ValidateMessage(p); err != nil {
-			cmd.PrintErrf("%s\n", err)
+		diagnostics := v.LintWithPaths(p, paths)
+
+		if !reportDiagnostics(cmd, diagnostics) {
 			os.Exit(1)
-		} else {
-			cmd.Println(status)
 		}
+		if fix && !useStdin {
+			cmd.Println(message)
+			return
+		}
+		cmd.Println("valid commit message")
 	},
 }
 
@@ -82,6 +141,8 @@ func init() {
 	// Add the "--stdin" flag to the message command
 	messageCmd.Flags().
 		BoolP("stdin", "s", false, "Read message from STDIN instead of arguments")
+	messageCmd.Flags().
+		Bool("fix", false, "Apply the available fixes to the message")
 
 	// Add the "message" command to the root command
 	rootCmd.AddCommand(messageCmd)
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+var parseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "Print the parsed structure of a Git commit message.",
+	Long: `Print the parsed structure of a Git commit message.
+
+Use this command to inspect how a commit message is split into its type, scope,
+description, body and footers, along with the location of each header component
+and the classification of every line. This is useful for debugging rejected
+messages and for bug reports. The comment lines Git discards (along with anything
+below the scissors line) are left out. The message is read from the given file or
+from STDIN if the file is omitted or "-".`,
+	Example: `crisp parse .git/COMMIT_EDITMSG
+git log -1 --format=%B | crisp parse --format json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		path := "-"
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		message, err := readInputFile(path)
+		if err != nil {
+			cmd.PrintErrf("error reading commit message: %s\n", err)
+			os.Exit(1)
+		}
+
+		// The comments are discarded by Git, e.g. in .git/COMMIT_EDITMSG
+		p, err := parser.ParseCommitMessage(parser.StripComments(message))
+		if err != nil {
+			cmd.PrintErrf("%s\n", err)
+			os.Exit(1)
+		}
+
+		switch format {
+		case "json":
+			out, err := json.MarshalIndent(p, "", "  ")
+			if err != nil {
+				cmd.PrintErrf("error encoding parse result: %s\n", err)
+				os.Exit(1)
+			}
+			cmd.Println(string(out))
+		case "tree":
+			cmd.Print(renderParseTree(p))
+		default:
+			cmd.PrintErrf("error: unknown format %q, expected tree or json\n", format)
+			os.Exit(1)
+		}
+	},
+}
+
+// renderParseTree renders the parsed commit message as a human-readable tree.
+func renderParseTree(p *parser.CommitMessage) string {
+	var b strings.Builder
+
+	span := func(s parser.Span) string {
+		return fmt.Sprintf("[%d:%d]", s.Start, s.End)
+	}
+
+	b.WriteString("CommitMessage\n")
+	fmt.Fprintf(&b, "├── type: %q %s\n", p.Type, span(p.Spans.Type))
+	if p.Spans.Scope != nil {
+		fmt.Fprintf(&b, "├── scope: %q %s\n", p.Scope, span(*p.Spans.Scope))
+	} else {
+		b.WriteString("├── scope: (none)\n")
+	}
+	fmt.Fprintf(
+		&b,
+		"├── description: %q %s\n",
+		p.Description,
+		span(p.Spans.Description),
+	)
+	fmt.Fprintf(&b, "├── body: %q\n", p.Body)
+
+	b.WriteString("├── footers\n")
+	for idx, trailer := range p.Trailers {
+		branch := "├──"
+		if idx == len(p.Trailers)-1 {
+			branch = "└──"
+		}
+		fmt.Fprintf(&b, "│   %s %s: %q\n", branch, trailer.Key, trailer.Value)
+	}
+
+	b.WriteString("├── references\n")
+	for idx, ref := range p.References {
+		branch := "├──"
+		if idx == len(p.References)-1 {
+			branch = "└──"
+		}
+		where := "body"
+		if ref.Footer != "" {
+			where = ref.Footer
+		}
+		fmt.Fprintf(
+			&b,
+			"│   %s %s %s (line %d, %s)\n",
+			branch,
+			ref.Kind,
+			ref.Text,
+			ref.Line,
+			where,
+		)
+	}
+
+	b.WriteString("└── lines\n")
+	for idx, line := range p.Lines {
+		branch := "├──"
+		if idx == len(p.Lines)-1 {
+			branch = "└──"
+		}
+		text := fmt.Sprintf("%s %3d %-7s %s", branch, line.Number, line.Kind, line.Text)
+		fmt.Fprintf(&b, "    %s\n", strings.TrimRight(text, " "))
+	}
+
+	return b.String()
+}
+
+func init() {
+	parseCmd.Flags().
+		StringP("format", "f", "tree", "Output format of the parse result (tree, json)")
+
+	rootCmd.AddCommand(parseCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/prepare"
+	"github.com/Weburz/crisp/internal/suggest"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var prepareCommitMsgCmd = &cobra.Command{
+	Use:   "prepare-commit-msg <file> [source] [sha]",
+	Short: "Pre-fill a commit message from the branch name.",
+	Long: `Pre-fill a commit message from the branch name.
+
+Use this command as a Git prepare-commit-msg hook to pre-fill the header and a
+"Refs" trailer of new commit messages from the name of the current branch, e.g.
+the message of a commit on the "fix/ABC-123-reader-stdin" branch is pre-filled
+with "fix(reader): " and "Refs: ABC-123". The scope is only pre-filled if it is a
+scope used by a recent commit or the name of a directory of the repository.
+
+The messages given with "-m" or "-F", merges, squashes and amended commits are left
+untouched, as are the comments added by Git. When run by the Pre-Commit framework,
+the source is taken from the PRE_COMMIT_COMMIT_MSG_SOURCE environment variable.
+
+With "--suggest", the header suggested from the staged changes (see "crisp suggest")
+is pre-filled when the branch name does not start with a type.`,
+	Example: `# Contents of the .git/hooks/prepare-commit-msg script
+exec crisp prepare-commit-msg "$@"`,
+	Args: cobra.RangeArgs(1, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := os.Getenv("PRE_COMMIT_COMMIT_MSG_SOURCE")
+		if len(args) > 1 {
+			source = args[1]
+		}
+		if !prepare.ShouldPrefill(source) {
+			return
+		}
+
+		repo := git.NewRepo("")
+		v := validator.NewValidatorWithConfig(loadConfig(cmd))
+
+		// Commits made on a detached HEAD (e.g. during a rebase) have no branch name
+		var info *validator.BranchInfo
+		if branch, err := repo.CurrentBranch(); err == nil {
+			info, err = v.ParseBranch(branch)
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		useSuggestion, _ := cmd.Flags().GetBool("suggest")
+		if useSuggestion && (info == nil || info.Type == "") {
+			changes, err := repo.StagedChanges()
+			if err == nil && len(changes) > 0 {
+				best := suggest.Suggest(v, changes)[0]
+				if info == nil {
+					info = &validator.BranchInfo{}
+				}
+				info.Type, info.Scope = best.Type, best.Scope
+			}
+		}
+
+		message, err := readInputFile(args[0])
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+
+		prefilled := prepare.Prefill(message, info, knownScopes(repo))
+		if prefilled == message {
+			return
+		}
+		if err := os.WriteFile(args[0], []byte(prefilled), 0o644); err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// knownScopes returns the scopes used by the recent commits of the repository along
+// with the names of all its directories.
+func knownScopes(repo *git.Repo) []string {
+	scopes := historyScopes(repo)
+
+	files, err := repo.TrackedFiles()
+	if err != nil {
+		return scopes
+	}
+	for _, file := range files {
+		for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+			if name := path.Base(dir); !slices.Contains(scopes, name) {
+				scopes = append(scopes, name)
+			}
+		}
+	}
+
+	return scopes
+}
+
+func init() {
+	prepareCommitMsgCmd.Flags().
+		Bool("suggest", false, "Fall back to the header suggested from the changes")
+
+	rootCmd.AddCommand(prepareCommitMsgCmd)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/hook"
+)
+
+var prePushCmd = &cobra.Command{
+	Use:   "pre-push [remote] [url]",
+	Short: "Lint the messages of every commit being pushed.",
+	Long: `Lint the messages of every commit being pushed.
+
+Use this command as a Git pre-push hook to lint the messages of all the new
+commits before they are pushed, including commits which never passed through the
+commit-msg hook (e.g. commits made with "--no-verify" or by a rebase). The ref
+updates are read from STDIN in the format Git passes them to the hook:
+
+  <local ref> <local sha> <remote ref> <remote sha>
+
+The new commits of a new branch are the ones unknown to the remote-tracking
+branches of the remote (or of all the remotes when pushing to a URL). When run by
+the Pre-Commit framework, the range is taken from the PRE_COMMIT_FROM_REF and
+PRE_COMMIT_TO_REF environment variables instead. The push is blocked if any of the
+commit messages are invalid.`,
+	Example: `# Contents of the .git/hooks/pre-push script
+exec crisp pre-push "$@"`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		remote := os.Getenv("PRE_COMMIT_REMOTE_NAME")
+		if len(args) > 0 {
+			remote = args[0]
+		}
+
+		var updates []hook.PushUpdate
+		if to := os.Getenv("PRE_COMMIT_TO_REF"); to != "" {
+			from := os.Getenv("PRE_COMMIT_FROM_REF")
+			if from == "" {
+				from = "0000000000000000000000000000000000000000"
+			}
+			updates = []hook.PushUpdate{{LocalSHA: to, RemoteSHA: from}}
+		} else {
+			var err error
+			updates, err = hook.ParsePrePush(cmd.InOrStdin())
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		repo := git.NewRepo("")
+		shas := []string{}
+		for _, u := range updates {
+			commits, err := u.Commits(repo, remote)
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+
+			// The same commits are pushed more than once if multiple refs point to them
+			for _, sha := range commits {
+				if !slices.Contains(shas, sha) {
+					shas = append(shas, sha)
+				}
+			}
+		}
+
+		ok, err := lintCommits(cmd, repo, shas)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prePushCmd)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/hook"
+)
+
+var preReceiveCmd = &cobra.Command{
+	Use:   "pre-receive [<ref> <old sha> <new sha>]",
+	Short: "Lint the messages of every commit received by a server.",
+	Long: `Lint the messages of every commit received by a server.
+
+Use this command as a server-side pre-receive hook of a (bare) repository to
+enforce valid commit messages centrally. The ref updates are read from STDIN in
+the format Git passes them to the hook:
+
+  <old sha> <new sha> <ref>
+
+When the ref, the old and the new object name are passed as arguments instead,
+the command behaves as an update hook and only lints the given ref. New refs and
+deletions are handled and only the commits not yet reachable from any existing ref
+are linted. The diagnostics are written to STDERR which Git relays to the pusher
+and the whole push (or the ref, for the update hook) is rejected on violations.`,
+	Example: `# Contents of the hooks/pre-receive script of a bare repository
+exec crisp pre-receive
+
+# Contents of the hooks/update script of a bare repository
+exec crisp pre-receive "$@"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 3 {
+			return errors.New("expected no arguments or <ref> <old sha> <new sha>")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var updates []hook.RefUpdate
+		if len(args) == 3 {
+			updates = []hook.RefUpdate{{Ref: args[0], OldSHA: args[1], NewSHA: args[2]}}
+		} else {
+			var err error
+			updates, err = hook.ParsePreReceive(cmd.InOrStdin())
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		repo := git.NewRepo("")
+		shas := []string{}
+		for _, u := range updates {
+			commits, err := u.Commits(repo)
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+
+			// The same commits are received more than once if multiple refs point to
+			// them
+			for _, sha := range commits {
+				if !slices.Contains(shas, sha) {
+					shas = append(shas, sha)
+				}
+			}
+		}
+
+		ok, err := lintCommits(cmd, repo, shas)
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+		if !ok {
+			cmd.PrintErrln("error: push rejected, fix the commit messages and retry")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(preReceiveCmd)
+}
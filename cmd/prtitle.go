@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/ci"
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var prTitleCmd = &cobra.Command{
+	Use:   "pr-title",
+	Short: "Lint the title of a pull request.",
+	Long: `Lint the title of a pull request.
+
+Use this command in the CI pipelines of repositories which squash-merge pull (or
+merge) requests, where the title of the pull request becomes the header of the
+commit message. The title is validated by the rules checking the commit message
+header (the rules requiring a body or footers do not apply) and is read from
+"--title", the event payload at GITHUB_EVENT_PATH (GitHub, Gitea and Forgejo
+Actions) or the CI_MERGE_REQUEST_TITLE environment variable (GitLab CI).
+
+With "--full", the title and the description of the pull request are linted
+together as the commit message the squash-merge will result in.`,
+	Example: `crisp pr-title
+crisp pr-title --full
+crisp pr-title --title "feat(cli): add the pr-title command"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		title, _ := cmd.Flags().GetString("title")
+		description, _ := cmd.Flags().GetString("description")
+		full, _ := cmd.Flags().GetBool("full")
+
+		pr := &ci.PullRequest{Title: title, Description: description}
+		if title == "" {
+			var err error
+			pr, err = ci.PullRequestFromEnv(os.Getenv)
+			if errors.Is(err, ci.ErrNoPullRequest) {
+				cmd.PrintErrf("error: %s, pass its title with --title\n", err)
+				os.Exit(1)
+			}
+			if err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if !lintPullRequest(cmd, loadConfig(cmd), pr, full) {
+			os.Exit(1)
+		}
+	},
+}
+
+// lintPullRequest lints the title of the pull request, or its squash commit message if
+// full is true, and prints the diagnostics. The title is redacted in the report like
+// it is in the diagnostics. Returns false if the pull request was rejected.
+func lintPullRequest(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	pr *ci.PullRequest,
+	full bool,
+) bool {
+	// A lone title is only checked by the rules validating the header, since it can
+	// not carry the body and the footers other rules may require
+	var diagnostics []string
+	var ok bool
+	subject := "pull request title"
+	if full {
+		diagnostics, ok = lintMessage(cfg, pr.Message(), nil, nil)
+		subject = "squash commit message"
+	} else {
+		diagnostics, ok = lintTitle(cfg, pr.Title)
+	}
+
+	for _, d := range diagnostics {
+		cmd.PrintErrln(d)
+	}
+	if !ok {
+		cmd.PrintErrf(
+			"\ninvalid %s: %q\n"+
+				"info: run \"crisp explain <RULE>\" to learn more about a rule\n",
+			subject,
+			validator.NewValidatorWithConfig(cfg).Redact(pr.Title),
+		)
+		return false
+	}
+
+	cmd.Printf("valid %s\n", subject)
+	return true
+}
+
+// lintTitle parses and lints a pull request title as a lone commit message header and
+// returns the rendered diagnostics (or the redacted parse error) along with whether
+// the title was accepted.
+func lintTitle(cfg *config.Config, title string) ([]string, bool) {
+	v := validator.NewValidatorWithConfig(cfg)
+	p, err := parser.ParseCommitMessage(title)
+	if err != nil {
+		return []string{v.Redact(err.Error())}, false
+	}
+
+	diagnostics := v.LintHeader(p)
+	rendered := []string{}
+	for _, d := range diagnostics {
+		rendered = append(rendered, d.String())
+	}
+
+	return rendered, !validator.HasErrors(diagnostics)
+}
+
+func init() {
+	prTitleCmd.Flags().String("title", "", "Title of the pull request to lint")
+	prTitleCmd.Flags().
+		String("description", "", "Description of the pull request (with --full)")
+	prTitleCmd.Flags().
+		Bool("full", false, "Lint the title and description as the squash commit")
+
+	rootCmd.AddCommand(prTitleCmd)
+}
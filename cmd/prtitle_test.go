@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/ci"
+	"github.com/Weburz/crisp/internal/config"
+)
+
+func TestLintPullRequest_RedactsTitle(t *testing.T) {
+	for _, title := range []string{"fix: use " + token, "use " + token} {
+		for _, full := range []bool{false, true} {
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+			cmd.SetErr(&out)
+
+			pr := &ci.PullRequest{Title: title}
+			if lintPullRequest(cmd, config.Default(), pr, full) {
+				t.Errorf("%q: expected the pull request to be rejected", title)
+			}
+			if strings.Contains(out.String(), token) {
+				t.Errorf("%q: expected the title to be redacted, got:\n%s", title, &out)
+			}
+			if !strings.Contains(out.String(), "ghp_****") {
+				t.Errorf("%q: expected the redacted title, got:\n%s", title, &out)
+			}
+		}
+	}
+}
+
+func TestLintTitle_RedactsParseError(t *testing.T) {
+	diagnostics, ok := lintTitle(config.Default(), "use "+token)
+	if ok || len(diagnostics) != 1 {
+		t.Fatalf("expected the parse error, got %v", diagnostics)
+	}
+	if strings.Contains(diagnostics[0], token) {
+		t.Errorf("expected the parse error to be redacted, got %q", diagnostics[0])
+	}
+}
@@ -4,6 +4,9 @@
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/reader"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,4 +31,34 @@ func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
+
+	// Cobra writes the output of "cmd.Print*()" to STDERR unless configured otherwise,
+	// send it to STDOUT instead so that it can be piped into other tools
+	rootCmd.SetOut(os.Stdout)
+}
+
+// readInputFile reads the entire contents of the file at path, where "-" refers to
+// STDIN.
+func readInputFile(path string) (string, error) {
+	if path == "-" {
+		path = os.Stdin.Name()
+	}
+
+	r, err := reader.NewFileReader(path)
+	if err != nil {
+		return "", err
+	}
+
+	return r.Read()
+}
+
+// loadConfig loads the configuration of the repository in the current working
+// directory, exiting with an error if it is invalid.
+func loadConfig(cmd *cobra.Command) *config.Config {
+	cfg, err := config.Load(".")
+	if err != nil {
+		cmd.PrintErrf("error: %s\n", err)
+		os.Exit(1)
+	}
+	return cfg
 }
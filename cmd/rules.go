@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List all the validation rules.",
+	Long: `List all the validation rules.
+
+Use this command to list the ID and a summary of every rule commit messages are
+validated against. The "markdown" format renders the rules reference page of the
+documentations.`,
+	Example: `crisp rules
+crisp rules --format markdown`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		switch format {
+		case "text":
+			for _, r := range validator.Rules() {
+				cmd.Printf("%-16s %s\n", r.ID, r.Summary)
+			}
+		case "markdown":
+			cmd.Print(validator.RulesMarkdown())
+		default:
+			cmd.PrintErrf(
+				"error: unknown format %q, expected text or markdown\n",
+				format,
+			)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rulesCmd.Flags().
+		StringP("format", "f", "text", "Output format of the list (text, markdown)")
+
+	rootCmd.AddCommand(rulesCmd)
+}
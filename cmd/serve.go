@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/server"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run Crisp as an HTTP webhook service.",
+	Long: `Run Crisp as an HTTP webhook service.
+
+Use this command to run Crisp as a small internal service which lints the commit
+messages and pull request titles found in the push and pull/merge request webhook
+payloads of GitHub, GitLab and Gitea, and responds with a JSON report. The
+following endpoints are served:
+
+  POST /webhook   Lint a webhook payload (the provider is detected automatically)
+  POST /lint      Lint a raw commit message (or {"messages": [...]} as JSON)
+  GET  /healthz   Report the health of the service
+
+Webhook payloads are authenticated with the secret passed with "--secret" (or the
+CRISP_WEBHOOK_SECRET environment variable), the service refuses to start without
+one unless "--insecure" is passed. The messages are linted with the configuration
+file passed with "--config", or the .crisp.json file applying to the working
+directory, the pull request titles only being checked by the rules validating the
+header (like with "crisp pr-title"). The service shuts down gracefully on SIGINT
+and SIGTERM.`,
+	Example: `crisp serve --addr :8080 --secret "$WEBHOOK_SECRET"
+curl --data "feat: add a feature" http://localhost:8080/lint`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		secret, _ := cmd.Flags().GetString("secret")
+		timeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+		insecure, _ := cmd.Flags().GetBool("insecure")
+		configPath, _ := cmd.Flags().GetString("config")
+
+		if secret == "" {
+			secret = os.Getenv("CRISP_WEBHOOK_SECRET")
+		}
+		if secret == "" && !insecure {
+			cmd.PrintErrln("error: no secret set, pass --secret or --insecure")
+			os.Exit(1)
+		}
+		if secret == "" {
+			cmd.PrintErrln("warning: no secret set, webhooks are not authenticated")
+		}
+
+		var cfg *config.Config
+		if configPath == "" {
+			cfg = loadConfig(cmd)
+		} else {
+			var err error
+			if cfg, err = config.LoadFile(configPath); err != nil {
+				cmd.PrintErrf("error: %s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		srv := &http.Server{
+			Addr:              addr,
+			Handler:           server.NewServer(secret, cfg),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(
+			context.Background(),
+			os.Interrupt,
+			syscall.SIGTERM,
+		)
+		defer stop()
+
+		errs := make(chan error, 1)
+		go func() {
+			cmd.Printf("listening on %s\n", addr)
+			errs <- srv.ListenAndServe()
+		}()
+
+		select {
+		case err := <-errs:
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		case <-ctx.Done():
+		}
+
+		// Stop accepting new connections and wait for the in-flight requests to finish
+		cmd.Println("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := srv.Shutdown(shutdownCtx); err != nil &&
+			!errors.Is(err, http.ErrServerClosed) {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveCmd.Flags().
+		String("secret", "", "Secret used to authenticate webhook payloads")
+	serveCmd.Flags().
+		Duration("shutdown-timeout", 10*time.Second, "Grace period for open requests")
+	serveCmd.Flags().
+		Bool("insecure", false, "Accept unauthenticated webhooks if no secret is set")
+	serveCmd.Flags().
+		String("config", "", "Path to the configuration file to lint the messages with")
+
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/suggest"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggest a commit header from the staged changes.",
+	Long: `Suggest a commit header from the staged changes.
+
+Use this command before writing a commit message to get a ranked list of candidate
+headers (the type and scope) along with the reasons for each of them. The
+candidates are derived with deterministic heuristics from the staged files, e.g.
+whether the source files were added or modified, whether only documentation or
+test files were changed and whether the Go module dependencies were updated. The
+files are classified and the scopes are derived like the "type-changes" and
+"scope-paths" rules do, following the .crisp.json configuration.
+
+The "header" format prints the best candidate only, ready to be used as the start
+of a commit message.`,
+	Example: `crisp suggest
+git commit --edit --message "$(crisp suggest --format header)"`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		changes, err := git.NewRepo("").StagedChanges()
+		if err != nil {
+			cmd.PrintErrf("error: %s\n", err)
+			os.Exit(1)
+		}
+		if len(changes) == 0 {
+			cmd.PrintErrln("warning: no changes are staged")
+		}
+
+		v := validator.NewValidatorWithConfig(loadConfig(cmd))
+		candidates := suggest.Suggest(v, changes)
+
+		switch format {
+		case "text":
+			for idx, c := range candidates {
+				cmd.Printf("%d. %s\n", idx+1, strings.TrimSpace(c.Header()))
+				for _, reason := range c.Reasons {
+					cmd.Printf("   - %s\n", reason)
+				}
+			}
+		case "json":
+			out, err := json.MarshalIndent(candidates, "", "  ")
+			if err != nil {
+				cmd.PrintErrf("error encoding the candidates: %s\n", err)
+				os.Exit(1)
+			}
+			cmd.Println(string(out))
+		case "header":
+			cmd.Println(candidates[0].Header())
+		default:
+			cmd.PrintErrf(
+				"error: unknown format %q, expected text, json or header\n",
+				format,
+			)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	suggestCmd.Flags().
+		StringP("format", "f", "text", "Output format (text, json, header)")
+
+	rootCmd.AddCommand(suggestCmd)
+}
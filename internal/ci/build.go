@@ -0,0 +1,290 @@
+package ci
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/git"
+)
+
+var (
+	// ErrUnknownProvider is returned when Crisp is not run by any of the supported CI
+	// services.
+	ErrUnknownProvider = errors.New("no supported CI service detected")
+
+	// ErrShallowClone is returned when the commits needed to compute the range of a
+	// build are missing from a shallow clone of the repository.
+	ErrShallowClone = errors.New("the history of the shallow clone is too short")
+)
+
+// Build describes the pipeline run by a CI service and the commits it was triggered
+// for.
+type Build struct {
+	// Provider is the name of the CI service, e.g. "github" or "gitlab"
+	Provider string
+
+	// PullRequest reports whether the build is for a pull (or merge) request
+	PullRequest bool
+
+	// Base is the object name of the last commit before the range, if known
+	Base string
+
+	// BaseBranch is the branch the range was forked from (e.g. the target branch of
+	// a pull request), used when the base commit is not known
+	BaseBranch string
+
+	// Head is the object name of the last commit of the range
+	Head string
+}
+
+// provider describes how a CI service is detected and how its build is read from
+// the environment.
+type provider struct {
+	name   string
+	detect func(getenv func(string) string) bool
+	build  func(getenv func(string) string) (*Build, error)
+}
+
+// providers lists the supported CI services.
+var providers = []provider{
+	{
+		name:   "github",
+		detect: equals("GITHUB_ACTIONS", "true"),
+		build:  buildGitHub,
+	},
+	{
+		name:   "gitlab",
+		detect: equals("GITLAB_CI", "true"),
+		build: func(getenv func(string) string) (*Build, error) {
+			if getenv("CI_MERGE_REQUEST_IID") != "" {
+				return &Build{
+					PullRequest: true,
+					Base:        getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA"),
+					BaseBranch:  getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"),
+					Head:        getenv("CI_COMMIT_SHA"),
+				}, nil
+			}
+			return &Build{
+				Base:       getenv("CI_COMMIT_BEFORE_SHA"),
+				BaseBranch: getenv("CI_DEFAULT_BRANCH"),
+				Head:       getenv("CI_COMMIT_SHA"),
+			}, nil
+		},
+	},
+	{
+		name:   "bitbucket",
+		detect: isSet("BITBUCKET_BUILD_NUMBER"),
+		build: func(getenv func(string) string) (*Build, error) {
+			return &Build{
+				PullRequest: getenv("BITBUCKET_PR_ID") != "",
+				Base:        getenv("BITBUCKET_PR_DESTINATION_COMMIT"),
+				BaseBranch:  getenv("BITBUCKET_PR_DESTINATION_BRANCH"),
+				Head:        getenv("BITBUCKET_COMMIT"),
+			}, nil
+		},
+	},
+	{
+		name:   "jenkins",
+		detect: isSet("JENKINS_URL"),
+		build: func(getenv func(string) string) (*Build, error) {
+			if getenv("CHANGE_ID") != "" {
+				return &Build{
+					PullRequest: true,
+					BaseBranch:  getenv("CHANGE_TARGET"),
+					Head:        getenv("GIT_COMMIT"),
+				}, nil
+			}
+			return &Build{
+				Base: getenv("GIT_PREVIOUS_SUCCESSFUL_COMMIT"),
+				Head: getenv("GIT_COMMIT"),
+			}, nil
+		},
+	},
+	{
+		name:   "azure",
+		detect: equals("TF_BUILD", "True"),
+		build: func(getenv func(string) string) (*Build, error) {
+			if getenv("SYSTEM_PULLREQUEST_PULLREQUESTID") != "" {
+				target := getenv("SYSTEM_PULLREQUEST_TARGETBRANCH")
+				return &Build{
+					PullRequest: true,
+					BaseBranch:  strings.TrimPrefix(target, "refs/heads/"),
+					Head:        getenv("SYSTEM_PULLREQUEST_SOURCECOMMITID"),
+				}, nil
+			}
+			return &Build{Head: getenv("BUILD_SOURCEVERSION")}, nil
+		},
+	},
+	{
+		name:   "buildkite",
+		detect: equals("BUILDKITE", "true"),
+		build: func(getenv func(string) string) (*Build, error) {
+			pr := getenv("BUILDKITE_PULL_REQUEST")
+			if pr != "" && pr != "false" {
+				return &Build{
+					PullRequest: true,
+					BaseBranch:  getenv("BUILDKITE_PULL_REQUEST_BASE_BRANCH"),
+					Head:        getenv("BUILDKITE_COMMIT"),
+				}, nil
+			}
+			return &Build{Head: getenv("BUILDKITE_COMMIT")}, nil
+		},
+	},
+}
+
+// equals returns a detector checking that the environment variable key is value.
+func equals(key, value string) func(func(string) string) bool {
+	return func(getenv func(string) string) bool {
+		return getenv(key) == value
+	}
+}
+
+// isSet returns a detector checking that the environment variable key is not empty.
+func isSet(key string) func(func(string) string) bool {
+	return func(getenv func(string) string) bool {
+		return getenv(key) != ""
+	}
+}
+
+// buildGitHub reads the build of GitHub Actions (and the compatible Gitea and
+// Forgejo Actions) from its event payload.
+func buildGitHub(getenv func(string) string) (*Build, error) {
+	b := &Build{Head: getenv("GITHUB_SHA")}
+
+	path := getenv("GITHUB_EVENT_PATH")
+	if path == "" {
+		return b, nil
+	}
+
+	event, err := readGitHubEvent(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr := event.PullRequest; pr != nil {
+		// GITHUB_SHA is a merge commit of the pull request which is not linted
+		return &Build{
+			PullRequest: true,
+			Base:        pr.Base.SHA,
+			BaseBranch:  pr.Base.Ref,
+			Head:        pr.Head.SHA,
+		}, nil
+	}
+
+	if event.After != "" {
+		b.Head = event.After
+	}
+	b.Base = event.Before
+	b.BaseBranch = event.Repository.DefaultBranch
+
+	return b, nil
+}
+
+// Detect identifies the CI service Crisp is run by from the environment variables
+// returned by getenv and reads the build being run.
+func Detect(getenv func(string) string) (*Build, error) {
+	for _, p := range providers {
+		if !p.detect(getenv) {
+			continue
+		}
+
+		b, err := p.build(getenv)
+		if err != nil {
+			return nil, err
+		}
+		b.Provider = p.name
+		if b.Head == "" {
+			b.Head = "HEAD"
+		}
+		if git.IsZeroSHA(b.Base) {
+			b.Base = ""
+		}
+
+		return b, nil
+	}
+
+	return nil, ErrUnknownProvider
+}
+
+// HasBase reports whether the start of the range of the build is known. Only the
+// head commit is part of the range otherwise.
+func (b *Build) HasBase() bool {
+	return b.Base != "" || b.BaseBranch != ""
+}
+
+// Commits returns the non-merge commits introduced by the build, newest first. The
+// range starts after the base commit or, if not known, at the point the head commit
+// was forked from the base branch. A clear error is returned if the history needed
+// to compute the range is missing from a shallow clone.
+func (b *Build) Commits(repo *git.Repo) ([]string, error) {
+	if !repo.HasCommit(b.Head) {
+		return nil, missingCommit(repo, b.Head)
+	}
+
+	base := b.Base
+	if base != "" && !repo.HasCommit(base) {
+		// The base commit is unreachable after a force-push, so fall back to the branch
+		if b.BaseBranch == "" {
+			return nil, missingCommit(repo, base)
+		}
+		base = ""
+	}
+
+	if base == "" && b.BaseBranch != "" {
+		branch, err := resolveBranch(repo, b.BaseBranch)
+		if err != nil {
+			return nil, err
+		}
+
+		base, err = repo.MergeBase(branch, b.Head)
+		if err != nil {
+			if shallow, _ := repo.IsShallow(); shallow {
+				return nil, fmt.Errorf(
+					"%w: no common ancestor of %q and %s, fetch more history "+
+						"(e.g. with \"git fetch --unshallow\")",
+					ErrShallowClone,
+					b.BaseBranch,
+					b.Head,
+				)
+			}
+			return nil, err
+		}
+	}
+
+	if base == "" {
+		return repo.RevList("--no-merges", "--max-count=1", b.Head)
+	}
+	return repo.RevList("--no-merges", base+".."+b.Head)
+}
+
+// resolveBranch returns the name of the ref the branch is known as in the repository,
+// preferring the remote-tracking branch since CI services seldom create local ones.
+func resolveBranch(repo *git.Repo, branch string) (string, error) {
+	refs := []string{"refs/remotes/origin/" + branch, "refs/heads/" + branch}
+	for _, ref := range refs {
+		if repo.HasCommit(ref) {
+			return ref, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"branch %q is not fetched (fetch it with \"git fetch origin %s\")",
+		branch,
+		branch,
+	)
+}
+
+// missingCommit returns the error reported when the commit named by rev is not in
+// the repository, which is most likely due to a shallow clone.
+func missingCommit(repo *git.Repo, rev string) error {
+	if shallow, _ := repo.IsShallow(); shallow {
+		return fmt.Errorf(
+			"%w: commit %s is missing, fetch more history "+
+				"(e.g. with \"git fetch --unshallow\")",
+			ErrShallowClone,
+			rev,
+		)
+	}
+	return fmt.Errorf("commit %s does not exist in the repository", rev)
+}
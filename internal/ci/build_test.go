@@ -0,0 +1,221 @@
+package ci
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/git/gittest"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Build
+	}{
+		{
+			name: "github pull request",
+			env: map[string]string{
+				"GITHUB_ACTIONS": "true",
+				"GITHUB_SHA":     "ffffffffffffffffffffffffffffffffffffffff",
+				"GITHUB_EVENT_PATH": filepath.Join(
+					"testdata",
+					"github-pull-request.json",
+				),
+			},
+			want: Build{
+				Provider:    "github",
+				PullRequest: true,
+				Base:        "6113728f27ae82c7b1a177c8d03f9e96e0adf246",
+				BaseBranch:  "main",
+				Head:        "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+			},
+		},
+		{
+			name: "github push of a new branch",
+			env: map[string]string{
+				"GITHUB_ACTIONS":    "true",
+				"GITHUB_EVENT_PATH": filepath.Join("testdata", "github-push.json"),
+			},
+			want: Build{
+				Provider:   "github",
+				BaseBranch: "main",
+				Head:       "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+			},
+		},
+		{
+			name: "gitlab merge request",
+			env: map[string]string{
+				"GITLAB_CI":                           "true",
+				"CI_MERGE_REQUEST_IID":                "3",
+				"CI_MERGE_REQUEST_DIFF_BASE_SHA":      "aaaa",
+				"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "main",
+				"CI_COMMIT_SHA":                       "bbbb",
+			},
+			want: Build{
+				Provider:    "gitlab",
+				PullRequest: true,
+				Base:        "aaaa",
+				BaseBranch:  "main",
+				Head:        "bbbb",
+			},
+		},
+		{
+			name: "bitbucket push",
+			env: map[string]string{
+				"BITBUCKET_BUILD_NUMBER": "12",
+				"BITBUCKET_COMMIT":       "bbbb",
+			},
+			want: Build{Provider: "bitbucket", Head: "bbbb"},
+		},
+		{
+			name: "jenkins change request",
+			env: map[string]string{
+				"JENKINS_URL":   "https://ci.example.com",
+				"CHANGE_ID":     "5",
+				"CHANGE_TARGET": "develop",
+				"GIT_COMMIT":    "bbbb",
+			},
+			want: Build{
+				Provider:    "jenkins",
+				PullRequest: true,
+				BaseBranch:  "develop",
+				Head:        "bbbb",
+			},
+		},
+		{
+			name: "azure pull request",
+			env: map[string]string{
+				"TF_BUILD":                          "True",
+				"SYSTEM_PULLREQUEST_PULLREQUESTID":  "9",
+				"SYSTEM_PULLREQUEST_TARGETBRANCH":   "refs/heads/main",
+				"SYSTEM_PULLREQUEST_SOURCECOMMITID": "bbbb",
+			},
+			want: Build{
+				Provider:    "azure",
+				PullRequest: true,
+				BaseBranch:  "main",
+				Head:        "bbbb",
+			},
+		},
+		{
+			name: "buildkite push",
+			env: map[string]string{
+				"BUILDKITE":              "true",
+				"BUILDKITE_PULL_REQUEST": "false",
+			},
+			want: Build{Provider: "buildkite", Head: "HEAD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(env(tt.env))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Detect() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_Unknown(t *testing.T) {
+	_, err := Detect(env(map[string]string{"CI": "true"}))
+	if !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("expected ErrUnknownProvider, got %v", err)
+	}
+}
+
+// newTestRepo initialises a repository whose "main" branch has two commits and whose
+// "topic" branch forks from it with two more commits, and returns its directory.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := gittest.NewRepo(t)
+	for _, message := range []string{"feat: first", "feat: second"} {
+		gittest.Commit(t, dir, message)
+	}
+	gittest.Run(t, dir, "checkout", "--quiet", "-b", "topic")
+	for _, message := range []string{"feat: third", "feat: fourth"} {
+		gittest.Commit(t, dir, message)
+	}
+
+	return dir
+}
+
+func TestBuild_Commits(t *testing.T) {
+	dir := newTestRepo(t)
+	repo := git.NewRepo(dir)
+	first := gittest.Run(t, dir, "rev-parse", "main~1")
+
+	tests := []struct {
+		name  string
+		build Build
+		want  int
+	}{
+		{"base commit", Build{Base: first, Head: "topic"}, 3},
+		{"base branch", Build{BaseBranch: "main", Head: "topic"}, 2},
+		{
+			"unreachable base commit",
+			Build{Base: strings.Repeat("1", 40), BaseBranch: "main", Head: "topic"},
+			2,
+		},
+		{"head only", Build{Head: "topic"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.build.Commits(repo)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("expected %d commits, got %d", tt.want, len(got))
+			}
+		})
+	}
+}
+
+func TestBuild_Commits_Errors(t *testing.T) {
+	dir := newTestRepo(t)
+	repo := git.NewRepo(dir)
+
+	build := Build{BaseBranch: "develop", Head: "topic"}
+	if _, err := build.Commits(repo); err == nil ||
+		!strings.Contains(err.Error(), "not fetched") {
+		t.Errorf("expected unfetched branch error, got %v", err)
+	}
+
+	build = Build{Base: strings.Repeat("1", 40), Head: "topic"}
+	if _, err := build.Commits(repo); err == nil ||
+		errors.Is(err, ErrShallowClone) {
+		t.Errorf("expected missing commit error, got %v", err)
+	}
+}
+
+func TestBuild_Commits_ShallowClone(t *testing.T) {
+	dir := newTestRepo(t)
+	first := gittest.Run(t, dir, "rev-parse", "main~1")
+
+	clone := t.TempDir()
+	gittest.Run(t, dir, "clone", "--quiet", "--depth", "1", "--branch", "topic",
+		"file://"+dir, clone)
+
+	build := Build{Base: first, Head: "HEAD"}
+	if _, err := build.Commits(git.NewRepo(clone)); !errors.Is(err, ErrShallowClone) {
+		t.Errorf("expected ErrShallowClone, got %v", err)
+	}
+
+	gittest.Run(t, clone, "fetch", "--quiet", "--depth", "1", "origin",
+		"main:refs/remotes/origin/main")
+	build = Build{BaseBranch: "main", Head: "HEAD"}
+	if _, err := build.Commits(git.NewRepo(clone)); !errors.Is(err, ErrShallowClone) {
+		t.Errorf("expected ErrShallowClone, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Package ci reads the context of the continuous integration service Crisp is run
+// in, like the pull request being built and the range of commits it introduces.
+package ci
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrNoPullRequest is returned when no pull request could be found in the
+// environment of the CI service.
+var ErrNoPullRequest = errors.New("no pull request found in the environment")
+
+// PullRequest holds the parts of a pull (or merge) request which end up in the
+// commit message when it is squash-merged.
+type PullRequest struct {
+	Title       string
+	Description string
+}
+
+// htmlComment matches the HTML comments pull request templates are commonly
+// annotated with, which are not rendered and are not part of the description.
+var htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+// Message returns the commit message a squash-merge of the pull request results
+// in, with the title as the header and the description as the body.
+func (pr *PullRequest) Message() string {
+	description := strings.ReplaceAll(pr.Description, "\r\n", "\n")
+	description = strings.TrimSpace(htmlComment.ReplaceAllString(description, ""))
+
+	if description == "" {
+		return pr.Title
+	}
+	return pr.Title + "\n\n" + description
+}
+
+// PullRequestFromEnv looks up the pull request being built from the environment
+// variables returned by getenv. The event payload of GitHub Actions (and the
+// compatible Gitea and Forgejo Actions) is read from GITHUB_EVENT_PATH, while GitLab
+// CI exposes the merge request in the CI_MERGE_REQUEST_* variables.
+func PullRequestFromEnv(getenv func(string) string) (*PullRequest, error) {
+	if path := getenv("GITHUB_EVENT_PATH"); path != "" {
+		event, err := readGitHubEvent(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// The workflow was triggered by another event than a pull request (e.g. a push)
+		if event.PullRequest == nil {
+			return nil, ErrNoPullRequest
+		}
+
+		return &PullRequest{
+			Title:       event.PullRequest.Title,
+			Description: event.PullRequest.Body,
+		}, nil
+	}
+
+	if title := getenv("CI_MERGE_REQUEST_TITLE"); title != "" {
+		return &PullRequest{
+			Title:       title,
+			Description: getenv("CI_MERGE_REQUEST_DESCRIPTION"),
+		}, nil
+	}
+
+	return nil, ErrNoPullRequest
+}
+
+// githubEvent is the subset of the GitHub Actions event payloads Crisp is interested
+// in, covering both the "push" and "pull_request" events.
+type githubEvent struct {
+	Before      string `json:"before"`
+	After       string `json:"after"`
+	PullRequest *struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		Base  struct {
+			Ref string `json:"ref"`
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+}
+
+// readGitHubEvent reads the GitHub Actions event payload at path.
+func readGitHubEvent(path string) (*githubEvent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the event payload: %w", err)
+	}
+
+	var event githubEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid event payload: %w", err)
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,63 @@
+package ci
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// env returns a getenv function looking up the variables in vars.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestPullRequestFromEnv_GitHub(t *testing.T) {
+	pr, err := PullRequestFromEnv(env(map[string]string{
+		"GITHUB_EVENT_PATH": filepath.Join("testdata", "github-pull-request.json"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pr.Title != "feat(cli): add the pr-title command" {
+		t.Errorf("unexpected title: %q", pr.Title)
+	}
+
+	want := "feat(cli): add the pr-title command\n\n" +
+		"Lint the title of pull requests.\n\nRefs: #7"
+	if got := pr.Message(); got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestPullRequestFromEnv_GitHubPush(t *testing.T) {
+	_, err := PullRequestFromEnv(env(map[string]string{
+		"GITHUB_EVENT_PATH": filepath.Join("testdata", "github-push.json"),
+	}))
+	if !errors.Is(err, ErrNoPullRequest) {
+		t.Errorf("expected ErrNoPullRequest, got %v", err)
+	}
+}
+
+func TestPullRequestFromEnv_GitLab(t *testing.T) {
+	pr, err := PullRequestFromEnv(env(map[string]string{
+		"CI_MERGE_REQUEST_TITLE":       "fix: handle empty stdin",
+		"CI_MERGE_REQUEST_DESCRIPTION": "",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := pr.Message(); got != "fix: handle empty stdin" {
+		t.Errorf("Message() = %q, want %q", got, "fix: handle empty stdin")
+	}
+}
+
+func TestPullRequestFromEnv_None(t *testing.T) {
+	_, err := PullRequestFromEnv(env(map[string]string{}))
+	if !errors.Is(err, ErrNoPullRequest) {
+		t.Errorf("expected ErrNoPullRequest, got %v", err)
+	}
+}
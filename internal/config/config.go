@@ -0,0 +1,465 @@
+// Package config loads the per-repository configuration of Crisp from the
+// ".crisp.json" file at the root of the repository (or any of its parent directories).
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/textwidth"
+)
+
+// FileName is the name of the configuration file.
+const FileName = ".crisp.json"
+
+// Config holds the settings of Crisp. Every setting left out of the configuration
+// file keeps its default value.
+type Config struct {
+	// Rules sets the severity of the rules by their ID, either "error", "warning" or
+	// "off". The optional rules are only run if they are given a severity.
+	Rules map[string]string `json:"rules"`
+
+	// Scopes maps each scope to the glob patterns of the paths it covers. The scopes
+	// are derived from the directories of the paths if no mapping is provided.
+	Scopes map[string][]string `json:"scopes"`
+
+	// Classes maps each class of files to the glob patterns of its paths, the paths
+	// not matching any of them are source files. The configured patterns of a class
+	// replace its default ones.
+	Classes map[string][]string `json:"classes"`
+
+	Header     Header     `json:"header"`
+	Subject    Subject    `json:"subject"`
+	Mood       Mood       `json:"mood"`
+	Branch     Branch     `json:"branch"`
+	Body       Body       `json:"body"`
+	Secrets    Secrets    `json:"secrets"`
+	Terms      Terms      `json:"terms"`
+	References References `json:"references"`
+	SignOff    SignOff    `json:"signOff"`
+}
+
+// Severities lists the severities the rules can be configured with.
+var Severities = []string{"error", "warning", "off"}
+
+// RuleIDs lists the IDs of the rules the severity can be configured of, in the order
+// they are run by the validator.
+var RuleIDs = []string{
+	"header-length",
+	"type",
+	"scope-case",
+	"subject",
+	"imperative-mood",
+	"body-separator",
+	"body-blank-lines",
+	"body-max-line-length",
+	"trailing-whitespace",
+	"footer-key",
+	"secrets",
+	"banned-terms",
+	"issue-reference",
+	"signed-off-by",
+	"scope-paths",
+	"type-changes",
+	"branch-name",
+}
+
+// FileClasses lists the classes the changed files are sorted into, in the order they
+// are tried. The files not matching any of them are source files.
+var FileClasses = []string{"test", "docs", "ci", "build"}
+
+// Branch holds the settings of the branch name validation.
+type Branch struct {
+	// Patterns lists the regular expressions branch names must match at least one of.
+	// The "{type}" placeholder is expanded into the allowed commit types.
+	Patterns []string `json:"patterns"`
+
+	// Exempt lists the regular expressions of the branch names which are not
+	// validated, like the long-lived branches.
+	Exempt []string `json:"exempt"`
+}
+
+// Header holds the settings of the header length validation.
+type Header struct {
+	// MaxLength is the maximum length of the whole header.
+	MaxLength int `json:"maxLength"`
+
+	// MaxDescriptionLength is the maximum length of the description alone, it is not
+	// limited if zero.
+	MaxDescriptionLength int `json:"maxDescriptionLength"`
+
+	// Unit is the unit the lengths are measured in, either "columns" (the width of
+	// the text in a terminal, where East Asian characters and emoji take two columns)
+	// or "graphemes" (the number of user-perceived characters).
+	Unit string `json:"unit"`
+}
+
+// Subject holds the settings of the description casing validation.
+type Subject struct {
+	// Exceptions lists the words the description may start with as they are written
+	// (e.g. "GitHub"), in addition to the acronyms which are always accepted.
+	Exceptions []string `json:"exceptions"`
+}
+
+// Mood holds the settings of the imperative mood validation.
+type Mood struct {
+	// Allow lists the words the description may start with although they look like
+	// verbs in the past tense, the third person or the gerund.
+	Allow []string `json:"allow"`
+}
+
+// Body holds the settings of the rules validating the body of commit messages.
+type Body struct {
+	// MaxLineLength is the maximum number of characters of the lines of the body.
+	MaxLineLength int `json:"maxLineLength"`
+}
+
+// Secrets holds the settings of the detection of secrets in commit messages.
+type Secrets struct {
+	// Allow lists the fingerprints of the reported secrets which are not secrets,
+	// like the placeholders in examples.
+	Allow []string `json:"allow"`
+
+	// Entropy is the minimum entropy, in bits per character, of the random looking
+	// strings reported as secrets. They are not reported if zero.
+	Entropy float64 `json:"entropy"`
+}
+
+// Terms holds the settings of the banned words and phrases.
+type Terms struct {
+	// Banned lists the words and phrases commit messages must not contain. The
+	// configured terms replace the default ones.
+	Banned []Term `json:"banned"`
+}
+
+// Term is a word or a phrase commit messages must not contain.
+type Term struct {
+	// Phrase is the banned word or phrase, matched on word boundaries ignoring the
+	// case and the amount of whitespace between the words.
+	Phrase string `json:"phrase"`
+
+	// Replacement is the suggested alternative to the phrase, if any.
+	Replacement string `json:"replacement"`
+
+	// Severity is the severity of the violations, either "error" or "warning"
+	// (the default).
+	Severity string `json:"severity"`
+
+	// In is the part of the message the phrase is banned from, either
+	// "description", "body" or "both" (the default).
+	In string `json:"in"`
+
+	// Exact only bans the phrase when it makes up the whole description or a whole
+	// line of the body, e.g. a description reading "update".
+	Exact bool `json:"exact"`
+}
+
+// TermSeverities lists the severities the banned terms can be configured with.
+var TermSeverities = []string{"error", "warning"}
+
+// TermLocations lists the parts of the message the terms can be banned from.
+var TermLocations = []string{"description", "body", "both"}
+
+// References holds the settings of the issue references validation.
+type References struct {
+	// Types lists the types of the commit messages which must reference an issue.
+	Types []string `json:"types"`
+
+	// Body accepts the references found in the body in addition to the ones in the
+	// "Closes", "Fixes" and "Refs" footers.
+	Body bool `json:"body"`
+
+	// Projects lists the keys of the projects whose Jira-style keys (e.g. "PROJ-123")
+	// are accepted as references.
+	Projects []string `json:"projects"`
+}
+
+// SignOff holds the settings of the sign-off validation.
+type SignOff struct {
+	// CoAuthors is the policy applied to the co-authors of the commits, either
+	// "ignore" or "require" (every co-author must sign off as well).
+	CoAuthors string `json:"coAuthors"`
+}
+
+// CoAuthorPolicies lists the policies the co-authors can be configured with.
+var CoAuthorPolicies = []string{"ignore", "require"}
+
+// Default returns the configuration used when no configuration file is found.
+func Default() *Config {
+	cfg := &Config{
+		Rules:  map[string]string{},
+		Scopes: map[string][]string{},
+		Classes: map[string][]string{
+			"test": {
+				"**/*_test.go",
+				"**/testdata/**",
+				"**/test/**",
+				"**/tests/**",
+				"**/__tests__/**",
+				"*.test.*",
+				"*.spec.*",
+			},
+			"docs": {
+				"docs/**",
+				"doc/**",
+				"*.md",
+				"*.mdx",
+				"*.rst",
+				"*.adoc",
+				"LICENSE",
+			},
+			"ci": {
+				".github/workflows/**",
+				".github/actions/**",
+				".gitlab-ci.yml",
+				".circleci/**",
+				".buildkite/**",
+				"azure-pipelines.yml",
+				"bitbucket-pipelines.yml",
+				"Jenkinsfile",
+				".pre-commit-config.yaml",
+				".pre-commit-hooks.yaml",
+			},
+			"build": {
+				"go.mod",
+				"go.sum",
+				"Makefile",
+				"Taskfile.yml",
+				"Dockerfile",
+				".goreleaser.yaml",
+				".goreleaser.yml",
+				"package.json",
+				"package-lock.json",
+				"pnpm-lock.yaml",
+				"yarn.lock",
+			},
+		},
+		Branch: Branch{
+			Patterns: []string{`^{type}/[A-Za-z0-9][A-Za-z0-9._-]*$`},
+			Exempt: []string{
+				`^(main|master|develop|trunk)$`,
+				`^(release|dependabot|renovate)/`,
+			},
+		},
+		Header:  Header{MaxLength: 50, Unit: textwidth.Columns},
+		Subject: Subject{Exceptions: []string{}},
+		Mood:    Mood{Allow: []string{}},
+		Body:    Body{MaxLineLength: 72},
+		Secrets: Secrets{Allow: []string{}, Entropy: 4.5},
+		Terms: Terms{
+			Banned: []Term{
+				{Phrase: "whitelist", Replacement: "allowlist"},
+				{Phrase: "blacklist", Replacement: "denylist"},
+				{Phrase: "slave", Replacement: "replica"},
+				{Phrase: "wip", In: "description"},
+				{Phrase: "stuff", In: "description"},
+				{Phrase: "misc fixes", In: "description"},
+				{Phrase: "update", In: "description", Exact: true},
+			},
+		},
+		References: References{
+			Types:    []string{"feat", "fix"},
+			Projects: []string{},
+		},
+		SignOff: SignOff{CoAuthors: "ignore"},
+	}
+
+	for i := range cfg.Terms.Banned {
+		setTermDefaults(&cfg.Terms.Banned[i])
+	}
+
+	return cfg
+}
+
+// setTermDefaults fills in the settings left out of the term with their defaults.
+func setTermDefaults(term *Term) {
+	if term.Severity == "" {
+		term.Severity = "warning"
+	}
+	if term.In == "" {
+		term.In = "both"
+	}
+}
+
+// Parse decodes the configuration file contents in data on top of the defaults.
+// Unknown settings are rejected to catch typos early.
+func Parse(data []byte) (*Config, error) {
+	cfg := Default()
+
+	// Decoding the terms into the default ones would merge them element by element
+	defaultTerms := cfg.Terms.Banned
+	cfg.Terms.Banned = nil
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if cfg.Terms.Banned == nil {
+		cfg.Terms.Banned = defaultTerms
+	}
+
+	for class := range cfg.Classes {
+		if !slices.Contains(FileClasses, class) {
+			return nil, fmt.Errorf(
+				"invalid configuration: unknown class of files %q, expected one of: %s",
+				class,
+				strings.Join(FileClasses, ", "),
+			)
+		}
+	}
+
+	for id, severity := range cfg.Rules {
+		if !slices.Contains(RuleIDs, id) {
+			return nil, fmt.Errorf(
+				"invalid configuration: unknown rule %q, run \"crisp rules\" to list "+
+					"the rules",
+				id,
+			)
+		}
+		if !slices.Contains(Severities, severity) {
+			return nil, fmt.Errorf(
+				"invalid configuration: invalid severity %q of rule %q, "+
+					"expected one of: %s",
+				severity,
+				id,
+				strings.Join(Severities, ", "),
+			)
+		}
+	}
+
+	if cfg.Header.MaxLength <= 0 {
+		return nil, fmt.Errorf(
+			"invalid configuration: the maximum length of the header must be "+
+				"positive, got %d",
+			cfg.Header.MaxLength,
+		)
+	}
+
+	if cfg.Header.MaxDescriptionLength < 0 {
+		return nil, fmt.Errorf(
+			"invalid configuration: the maximum length of the description must not "+
+				"be negative, got %d",
+			cfg.Header.MaxDescriptionLength,
+		)
+	}
+
+	if !slices.Contains(textwidth.Units, cfg.Header.Unit) {
+		return nil, fmt.Errorf(
+			"invalid configuration: unknown unit %q of the header length, "+
+				"expected one of: %s",
+			cfg.Header.Unit,
+			strings.Join(textwidth.Units, ", "),
+		)
+	}
+
+	if cfg.Body.MaxLineLength <= 0 {
+		return nil, fmt.Errorf(
+			"invalid configuration: the maximum line length of the body must be "+
+				"positive, got %d",
+			cfg.Body.MaxLineLength,
+		)
+	}
+
+	if cfg.Secrets.Entropy < 0 {
+		return nil, fmt.Errorf(
+			"invalid configuration: the entropy threshold of the secrets must not be "+
+				"negative, got %g",
+			cfg.Secrets.Entropy,
+		)
+	}
+
+	if !slices.Contains(CoAuthorPolicies, cfg.SignOff.CoAuthors) {
+		return nil, fmt.Errorf(
+			"invalid configuration: unknown co-author policy %q, expected one of: %s",
+			cfg.SignOff.CoAuthors,
+			strings.Join(CoAuthorPolicies, ", "),
+		)
+	}
+
+	for i := range cfg.Terms.Banned {
+		term := &cfg.Terms.Banned[i]
+		setTermDefaults(term)
+
+		switch {
+		case strings.TrimSpace(term.Phrase) == "":
+			return nil, errors.New("invalid configuration: a banned term has no phrase")
+		case !slices.Contains(TermSeverities, term.Severity):
+			return nil, fmt.Errorf(
+				"invalid configuration: invalid severity %q of the banned term %q, "+
+					"expected one of: %s",
+				term.Severity,
+				term.Phrase,
+				strings.Join(TermSeverities, ", "),
+			)
+		case !slices.Contains(TermLocations, term.In):
+			return nil, fmt.Errorf(
+				"invalid configuration: invalid location %q of the banned term %q, "+
+					"expected one of: %s",
+				term.In,
+				term.Phrase,
+				strings.Join(TermLocations, ", "),
+			)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Find returns the path to the configuration file in dir or the closest of its
+// parent directories. Returns an empty path if none of them has one.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads the configuration file applying to dir, falling back to the defaults if
+// there is none.
+func Load(dir string) (*Config, error) {
+	path, err := Find(dir)
+	if err != nil || path == "" {
+		return Default(), err
+	}
+
+	return LoadFile(path)
+}
+
+// LoadFile reads the configuration file at path.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
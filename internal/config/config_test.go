@@ -0,0 +1,152 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`{"branch": {"patterns": ["^{type}-.+$"]}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"^{type}-.+$"}; !reflect.DeepEqual(cfg.Branch.Patterns, want) {
+		t.Errorf("Branch.Patterns = %q, want %q", cfg.Branch.Patterns, want)
+	}
+
+	// The settings left out keep their defaults
+	if !reflect.DeepEqual(cfg.Branch.Exempt, Default().Branch.Exempt) {
+		t.Errorf("Branch.Exempt = %q, want the default", cfg.Branch.Exempt)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	invalid := []string{
+		`{"branch": []}`,
+		`{"brnach": {}}`,
+		`{`,
+		`{"body": {"maxLineLength": 0}}`,
+		`{"header": {"maxLength": -1}}`,
+		`{"header": {"unit": "bytes"}}`,
+		`{"secrets": {"entropy": -1}}`,
+		`{"terms": {"banned": [{"phrase": " "}]}}`,
+		`{"terms": {"banned": [{"phrase": "wip", "severity": "off"}]}}`,
+		`{"terms": {"banned": [{"phrase": "wip", "in": "footers"}]}}`,
+		`{"signOff": {"coAuthors": "all"}}`,
+	}
+	for _, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("expected error for %s, got nil", data)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "internal", "parser")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+
+	// Without a configuration file, the defaults are used
+	cfg, err := Load(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults", cfg)
+	}
+
+	data := []byte(`{"branch": {"exempt": ["^main$"]}}`)
+	if err := os.WriteFile(filepath.Join(root, FileName), data, 0o644); err != nil {
+		t.Fatalf("failed to write configuration: %v", err)
+	}
+
+	cfg, err = Load(nested)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"^main$"}; !reflect.DeepEqual(cfg.Branch.Exempt, want) {
+		t.Errorf("Branch.Exempt = %q, want %q", cfg.Branch.Exempt, want)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crisp.json")
+	data := []byte(`{"rules": {"scope-case": "off"}}`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write configuration: %v", err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Rules["scope-case"]; got != "off" {
+		t.Errorf(`Rules["scope-case"] = %q, want "off"`, got)
+	}
+
+	// Unlike Load, a missing file is an error
+	if _, err := LoadFile(filepath.Join(t.TempDir(), FileName)); err == nil {
+		t.Error("expected an error for a missing configuration file")
+	}
+}
+
+func TestParse_Rules(t *testing.T) {
+	cfg, err := Parse([]byte(`{"rules": {"scope-paths": "warning"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Rules["scope-paths"] != "warning" {
+		t.Errorf("Rules = %v, want scope-paths set to warning", cfg.Rules)
+	}
+
+	if _, err := Parse([]byte(`{"rules": {"scope-paths": "warn"}}`)); err == nil {
+		t.Error("expected error for invalid severity, got nil")
+	}
+
+	if _, err := Parse([]byte(`{"rules": {"header-lenght": "off"}}`)); err == nil {
+		t.Error("expected error for unknown rule, got nil")
+	}
+}
+
+func TestParse_Classes(t *testing.T) {
+	cfg, err := Parse([]byte(`{"classes": {"docs": ["manual/**"]}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"manual/**"}; !reflect.DeepEqual(cfg.Classes["docs"], want) {
+		t.Errorf("Classes[docs] = %q, want %q", cfg.Classes["docs"], want)
+	}
+	if !reflect.DeepEqual(cfg.Classes["test"], Default().Classes["test"]) {
+		t.Errorf("Classes[test] = %q, want the default", cfg.Classes["test"])
+	}
+
+	if _, err := Parse([]byte(`{"classes": {"tests": []}}`)); err == nil {
+		t.Error("expected error for unknown class, got nil")
+	}
+}
+
+func TestParse_Terms(t *testing.T) {
+	cfg, err := Parse([]byte(`{"terms": {"banned": [{"phrase": "master"}]}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Term{{Phrase: "master", Severity: "warning", In: "both"}}
+	if !reflect.DeepEqual(cfg.Terms.Banned, want) {
+		t.Errorf("Terms.Banned = %+v, want %+v", cfg.Terms.Banned, want)
+	}
+
+	cfg, err = Parse([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Terms.Banned, Default().Terms.Banned) {
+		t.Errorf("Terms.Banned = %+v, want the default", cfg.Terms.Banned)
+	}
+}
@@ -0,0 +1,140 @@
+// Package editor implements an editor wrapper which re-opens the commit message in the
+// user's editor until it passes the lint (or is emptied to abort the commit), so that
+// a rejected commit message is never thrown away.
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// marker prefixes the comment lines inserted into the commit message by the wrapper.
+// Git strips them along with its own comments when the commit is recorded.
+const marker = "# crisp: "
+
+// envGuard is set in the environment of the launched editor to detect when the wrapper
+// is (mis)configured as its own editor.
+const envGuard = "CRISP_EDITING"
+
+// messageFiles lists the names of the files holding the messages of the commits being
+// made. The other files Git opens in the editor (e.g. the todo list of an interactive
+// rebase, the message of an annotated tag or a hunk being edited) are edited as they
+// are, without being linted.
+var messageFiles = []string{"COMMIT_EDITMSG"}
+
+// LintFunc lints a commit message (with the comments stripped) and returns the lines
+// describing its violations along with whether the message was accepted.
+type LintFunc func(message string) ([]string, bool)
+
+// editor launches the user's editor and lints the commit message it produced.
+type editor struct {
+	command string
+	lint    LintFunc
+}
+
+// The NewEditor() constructor creates an instance of the editor wrapper launching
+// command (a shell command like "vim" or "code --wait") and linting with lint.
+func NewEditor(command string, lint LintFunc) *editor {
+	return &editor{command: command, lint: lint}
+}
+
+// DefaultCommand returns the editor to launch from the environment, checking
+// CRISP_EDITOR, VISUAL and EDITOR in that order before falling back to "vi". The
+// GIT_EDITOR variable and the "core.editor" setting are not considered since they
+// refer to the wrapper itself.
+func DefaultCommand() string {
+	for _, key := range []string{"CRISP_EDITOR", "VISUAL", "EDITOR"} {
+		if val := strings.TrimSpace(os.Getenv(key)); val != "" {
+			return val
+		}
+	}
+	return "vi"
+}
+
+// Edit opens the commit message file at path in the editor until the message passes
+// the lint or is emptied. On a failed lint, the violations are inserted as comments at
+// the top of the message before the editor is re-opened. Any other file is opened in
+// the editor once, as it is.
+func (e *editor) Edit(path string) error {
+	if os.Getenv(envGuard) != "" {
+		return errors.New("the editor wrapper is configured as its own editor")
+	}
+	if !slices.Contains(messageFiles, filepath.Base(path)) {
+		return e.launch(path)
+	}
+
+	for {
+		if err := e.launch(path); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read commit message: %w", err)
+		}
+		content := removeMarkers(string(data))
+
+		// An empty message aborts the commit, which Git takes care of
+		message := strings.TrimLeft(parser.StripComments(content), " \t\n")
+		if strings.TrimSpace(message) == "" {
+			return os.WriteFile(path, []byte(content), 0o644)
+		}
+
+		violations, ok := e.lint(message)
+		if ok {
+			return os.WriteFile(path, []byte(content), 0o644)
+		}
+
+		annotated := annotate(content, violations)
+		if err := os.WriteFile(path, []byte(annotated), 0o644); err != nil {
+			return fmt.Errorf("failed to write commit message: %w", err)
+		}
+	}
+}
+
+// launch runs the editor on the file at path through the shell (as Git does) so that
+// the command can contain arguments.
+func (e *editor) launch(path string) error {
+	cmd := exec.Command("sh", "-c", e.command+` "$@"`, e.command, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), envGuard+"=1")
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", e.command, err)
+	}
+	return nil
+}
+
+// annotate inserts the violations as marked comment lines at the top of the message.
+func annotate(content string, violations []string) string {
+	lines := []string{marker + "the commit message was rejected, fix the following:"}
+	for _, v := range violations {
+		for _, line := range strings.Split(v, "\n") {
+			lines = append(lines, strings.TrimRight(marker+"  "+line, " "))
+		}
+	}
+	lines = append(lines, marker+"empty the commit message to abort the commit", "")
+
+	return strings.Join(lines, "\n") + content
+}
+
+// removeMarkers removes the comment lines previously inserted by annotate.
+func removeMarkers(content string) string {
+	lines := []string{}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if !strings.HasPrefix(line, strings.TrimSpace(marker)) {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.TrimLeft(strings.Join(lines, ""), "\n")
+}
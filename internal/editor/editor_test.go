@@ -0,0 +1,170 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lintHeader accepts messages whose first line starts with "feat: ".
+func lintHeader(message string) ([]string, bool) {
+	if strings.HasPrefix(message, "feat: ") {
+		return nil, true
+	}
+	return []string{"error[type]: invalid commit message type"}, false
+}
+
+// writeScript creates an executable shell script in dir and returns its path.
+func writeScript(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+content), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
+
+// writeMessage creates the commit message file with the given content.
+func writeMessage(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write commit message: %v", err)
+	}
+	return path
+}
+
+func TestEdit_ReopensUntilValid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMessage(t, dir, "\n# Please enter the commit message.\n")
+
+	// The first session writes an invalid message, the second one checks that the
+	// violations were inserted and fixes the message
+	script := writeScript(t, dir, "editor", `
+count="$(cat "$0.count" 2>/dev/null || echo 0)"
+echo $((count + 1)) > "$0.count"
+if [ "$count" = 0 ]; then
+	printf 'fest: add it\n# Please enter the commit message.\n' > "$1"
+else
+	grep -q '^# crisp:   error\[type\]' "$1" || exit 3
+	sed -i 's/^fest/feat/' "$1"
+fi
+`)
+
+	if err := NewEditor(script, lintHeader).Edit(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "feat: add it\n# Please enter the commit message.\n"
+	if string(data) != want {
+		t.Errorf("commit message = %q, want %q", data, want)
+	}
+
+	count, _ := os.ReadFile(script + ".count")
+	if strings.TrimSpace(string(count)) != "2" {
+		t.Errorf("expected the editor to be launched twice, got %s", count)
+	}
+}
+
+func TestEdit_EmptyMessageAborts(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMessage(t, dir, "fest: add it\n")
+
+	script := writeScript(t, dir, "editor", `printf '# only comments\n' > "$1"`)
+
+	if err := NewEditor(script, lintHeader).Edit(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "# only comments\n" {
+		t.Errorf("commit message = %q, want only the comments", data)
+	}
+}
+
+func TestEdit_OtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "git-rebase-todo")
+	todo := []byte("pick 1a2b3c4 fest: add it\n")
+	if err := os.WriteFile(path, todo, 0o644); err != nil {
+		t.Fatalf("failed to write rebase todo list: %v", err)
+	}
+
+	// The todo list is not a commit message, so it is not linted nor re-opened
+	script := writeScript(t, dir, "editor", `
+echo launched >> "$0.log"
+sed -i 's/^pick/reword/' "$1"
+`)
+
+	if err := NewEditor(script, lintHeader).Edit(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if want := "reword 1a2b3c4 fest: add it\n"; string(data) != want {
+		t.Errorf("rebase todo list = %q, want %q", data, want)
+	}
+
+	log, _ := os.ReadFile(script + ".log")
+	if strings.Count(string(log), "launched") != 1 {
+		t.Errorf("expected the editor to be launched once, got %q", log)
+	}
+}
+
+func TestEdit_EditorFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMessage(t, dir, "feat: add it\n")
+
+	script := writeScript(t, dir, "editor", "exit 1")
+
+	if err := NewEditor(script, lintHeader).Edit(path); err == nil {
+		t.Error("expected error when the editor fails, got nil")
+	}
+}
+
+func TestEdit_EditorWithArguments(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMessage(t, dir, "")
+
+	script := writeScript(t, dir, "editor", `printf '%s: add it\n' "$1" > "$2"`)
+
+	if err := NewEditor(script+" feat", lintHeader).Edit(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "feat: add it\n" {
+		t.Errorf("commit message = %q, want %q", data, "feat: add it\n")
+	}
+}
+
+func TestAnnotateAndRemoveMarkers(t *testing.T) {
+	content := "fest: add it\n"
+
+	annotated := annotate(content, []string{"error[type]: one\n\ninfo: two"})
+	if !strings.HasPrefix(annotated, marker) {
+		t.Errorf("expected annotated message to start with marker, got %q", annotated)
+	}
+	if got := removeMarkers(annotated); got != content {
+		t.Errorf("removeMarkers() = %q, want %q", got, content)
+	}
+}
+
+func TestDefaultCommand(t *testing.T) {
+	t.Setenv("CRISP_EDITOR", "")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano")
+
+	if got := DefaultCommand(); got != "nano" {
+		t.Errorf("DefaultCommand() = %q, want %q", got, "nano")
+	}
+
+	t.Setenv("CRISP_EDITOR", "code --wait")
+	if got := DefaultCommand(); got != "code --wait" {
+		t.Errorf("DefaultCommand() = %q, want %q", got, "code --wait")
+	}
+}
@@ -0,0 +1,148 @@
+// Package formatter re-emits parsed Git commit messages in their canonical form. On
+// top of the serialisation provided by the parser it reflows the body paragraphs to a
+// given width while leaving code blocks and lists untouched.
+package formatter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// DefaultWidth is the column at which body paragraphs are wrapped unless configured
+// otherwise. It matches the width recommended by Git for commit message bodies.
+const DefaultWidth = 72
+
+// listItem matches the first line of a bulleted or numbered list item.
+var listItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s`)
+
+// formatter holds the options used to format a commit message.
+type formatter struct {
+	width int
+}
+
+// The NewFormatter() constructor creates and returns an instance of the formatter
+// struct which wraps the body at the given width. A non-positive width disables
+// wrapping.
+func NewFormatter(width int) *formatter {
+	return &formatter{width: width}
+}
+
+// Format returns the canonical textual form of the commit message (without a trailing
+// newline). The message itself is not modified.
+func (f *formatter) Format(msg *parser.CommitMessage) string {
+	formatted := *msg
+	formatted.Scope = strings.TrimSpace(msg.Scope)
+	formatted.Description = strings.TrimSpace(msg.Description)
+	formatted.Body = f.WrapBody(msg.Body)
+	return formatted.String()
+}
+
+// FormatMessage formats the textual commit message, e.g. the contents of the
+// .git/COMMIT_EDITMSG file, ending it with a newline. The comment lines Git adds to
+// the commit message template are not formatted but kept at the end of the message
+// as they are, including everything below the scissors line.
+func (f *formatter) FormatMessage(message string) (string, error) {
+	msg, err := parser.ParseCommitMessage(parser.StripComments(message))
+	if err != nil {
+		return "", err
+	}
+
+	formatted := f.Format(msg) + "\n"
+	if comments := parser.TrailingComments(message); comments != "" {
+		formatted += "\n" + comments
+	}
+	return formatted, nil
+}
+
+// WrapBody reflows the prose paragraphs of a commit message body so that no line is
+// longer than the configured width. Paragraphs containing list items or indented lines
+// and fenced code blocks are kept as is apart from stripping trailing whitespaces.
+// Consecutive blank lines are collapsed into one.
+func (f *formatter) WrapBody(body string) string {
+	lines := strings.Split(body, "\n")
+	out := []string{}
+	paragraph := []string{}
+	inFence := false
+
+	// flush emits the buffered paragraph, reflowing it if it only contains prose
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		if f.width > 0 && isProse(paragraph) {
+			words := strings.Fields(strings.Join(paragraph, " "))
+			out = append(out, wrap(words, f.width)...)
+		} else {
+			out = append(out, paragraph...)
+		}
+		paragraph = []string{}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		trimmed := strings.TrimSpace(line)
+
+		// Everything inside of a fenced code block is emitted verbatim
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			inFence = !inFence
+			out = append(out, line)
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// isProse reports whether a paragraph consists of plain text only, i.e. it contains no
+// list items and no indented (code) lines.
+func isProse(paragraph []string) bool {
+	for _, line := range paragraph {
+		if line[0] == ' ' || line[0] == '\t' || listItem.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// wrap greedily distributes the words over lines no longer than width. Words longer
+// than the width (such as URLs) are placed on a line of their own.
+func wrap(words []string, width int) []string {
+	lines := []string{}
+	current := ""
+
+	for _, word := range words {
+		switch {
+		case current == "":
+			current = word
+		case len([]rune(current))+1+len([]rune(word)) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
@@ -0,0 +1,180 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+func TestWrapBody(t *testing.T) {
+	f := NewFormatter(30)
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "reflow long paragraph",
+			input: "This paragraph is definitely longer than thirty columns.",
+			want:  "This paragraph is definitely\nlonger than thirty columns.",
+		},
+		{
+			name:  "join short lines",
+			input: "short\nlines are\njoined",
+			want:  "short lines are joined",
+		},
+		{
+			name:  "collapse blank lines and strip trailing whitespace",
+			input: "first  \n\n\n\nsecond\t",
+			want:  "first\n\nsecond",
+		},
+		{
+			name:  "keep lists untouched",
+			input: "- an item which is longer than thirty columns\n- another",
+			want:  "- an item which is longer than thirty columns\n- another",
+		},
+		{
+			name:  "keep indented code untouched",
+			input: "    go test ./... -run TestSomethingVeryLong",
+			want:  "    go test ./... -run TestSomethingVeryLong",
+		},
+		{
+			name:  "keep fenced code untouched",
+			input: "```\nfoo   bar baz qux quux corge grault\n\n\nend\n```",
+			want:  "```\nfoo   bar baz qux quux corge grault\n\n\nend\n```",
+		},
+		{
+			name:  "long words get their own line",
+			input: "see https://www.conventionalcommits.org/en/v1.0.0/ for more",
+			want:  "see\nhttps://www.conventionalcommits.org/en/v1.0.0/\nfor more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.WrapBody(tt.input); got != tt.want {
+				t.Errorf("WrapBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapBody_Disabled(t *testing.T) {
+	input := "This paragraph is definitely longer than thirty columns."
+	if got := NewFormatter(0).WrapBody(input); got != input {
+		t.Errorf("WrapBody() = %q, want %q", got, input)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	input := "feat(parser):  add serialiser   \n\n\n" +
+		"The serialiser emits messages in canonical form so that they can be " +
+		"compared.   \n\n" +
+		"fixes: #12\nBREAKING-CHANGE: the old format is gone"
+
+	want := "feat(parser): add serialiser\n\n" +
+		"The serialiser emits messages in canonical form so\n" +
+		"that they can be compared.\n\n" +
+		"BREAKING CHANGE: the old format is gone\n" +
+		"Fixes: #12"
+
+	msg, err := parser.ParseCommitMessage(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := NewFormatter(50)
+	got := f.Format(msg)
+	if got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+
+	// Formatting an already canonical message must not change it
+	msg, err = parser.ParseCommitMessage(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again := f.Format(msg); again != got {
+		t.Errorf("Format() is not idempotent: got %q, want %q", again, got)
+	}
+}
+
+func TestFormat_Trailers(t *testing.T) {
+	input := "fix: handle it\n\nThe trailers are kept.\n\n" +
+		"Refs: #1\nRefs: #2\nSigned-off-by: Jane Doe <jane@example.com>\n" +
+		"Co-authored-by: John Doe <john@example.com>"
+
+	msg, err := parser.ParseCommitMessage(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := NewFormatter(30).Format(msg); got != input {
+		t.Errorf("Format() = %q, want %q", got, input)
+	}
+
+	// The trailers of a message without any footers are not reflowed into the body
+	input = "fix: handle it\n\nSigned-off-by: Jane Doe <jane@example.com>\n" +
+		"Co-authored-by: John Doe <john@example.com>"
+
+	msg, err = parser.ParseCommitMessage(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := NewFormatter(30).Format(msg); got != input {
+		t.Errorf("Format() = %q, want %q", got, input)
+	}
+
+	// The paragraphs following lines formatted like footers are kept apart
+	input = "fix: x\n\nIntro.\n\nRefs: #1\n\nMore explanation after this.\n"
+	got, err := NewFormatter(72).FormatMessage(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != input {
+		t.Errorf("FormatMessage() = %q, want %q", got, input)
+	}
+}
+
+func TestFormatMessage(t *testing.T) {
+	comments := "# Please enter the commit message for your changes. Lines starting\n" +
+		"# with '#' will be ignored, and an empty message aborts the commit.\n" +
+		"#\n# On branch main\n" +
+		"# ------------------------ >8 ------------------------\n" +
+		"# Do not modify or remove the line above.\n" +
+		"diff --git a/main.go b/main.go\n"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "canonical message with comments",
+			input: "fix: handle it\n\nThe body.\n\nRefs: #1\n\n" + comments,
+			want:  "fix: handle it\n\nThe body.\n\nRefs: #1\n\n" + comments,
+		},
+		{
+			name:  "comments are not reflowed",
+			input: "fix: handle it  \nThe body\nis joined.\n" + comments,
+			want:  "fix: handle it\n\nThe body is joined.\n\n" + comments,
+		},
+		{
+			name:  "message without comments",
+			input: "fix: handle it",
+			want:  "fix: handle it\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFormatter(72).FormatMessage(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package fuzzy finds the closest matches of misspelled words among a list of known
+// words, like the commit types or the footer keys, to suggest them to the user.
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+)
+
+// The costs of the edit operations. Swapping two characters and substituting a key
+// with one of its neighbours on the keyboard are cheaper than the other typos since
+// they are the most likely ones.
+const (
+	costAdjacent  = 2
+	costTranspose = 2
+	costEdit      = 3
+)
+
+// keyboard lists the rows of the QWERTY keyboard layout, offset like the keys of an
+// actual keyboard.
+var keyboard = []string{
+	"1234567890-",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+}
+
+// adjacent maps each key to the keys surrounding it on the keyboard.
+var adjacent = map[rune]string{}
+
+func init() {
+	for row, keys := range keyboard {
+		for col, key := range keys {
+			neighbours := []rune{}
+			for _, r := range []int{row - 1, row, row + 1} {
+				if r < 0 || r >= len(keyboard) {
+					continue
+				}
+
+				// The rows below are shifted right by half a key
+				from, to := col-1, col+1
+				switch r {
+				case row - 1:
+					from, to = col, col+1
+				case row + 1:
+					from, to = col-1, col
+				}
+				for c := max(from, 0); c <= to && c < len(keyboard[r]); c++ {
+					if r != row || c != col {
+						neighbours = append(neighbours, rune(keyboard[r][c]))
+					}
+				}
+			}
+			adjacent[key] = string(neighbours)
+		}
+	}
+}
+
+// substitution returns the cost of substituting a with b.
+func substitution(a, b rune) int {
+	switch {
+	case a == b:
+		return 0
+	case strings.ContainsRune(adjacent[a], b):
+		return costAdjacent
+	}
+	return costEdit
+}
+
+// Distance returns the weighted edit distance between a and b, ignoring the case.
+// Inserting, deleting and substituting characters costs 3, except for substituting a
+// character next to the other one on the keyboard and swapping two characters which
+// cost 2.
+func Distance(a, b string) int {
+	s, t := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+
+	// d[i][j] is the distance between the first i runes of s and the first j of t
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i * costEdit
+	}
+	for j := range d[0] {
+		d[0][j] = j * costEdit
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			d[i][j] = min(
+				d[i-1][j]+costEdit,
+				d[i][j-1]+costEdit,
+				d[i-1][j-1]+substitution(s[i-1], t[j-1]),
+			)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+costTranspose)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
+
+// threshold returns the maximum distance of a suggestion for the word, allowing a
+// single typo in short words and two in longer ones.
+func threshold(word string) int {
+	if len([]rune(word)) <= 4 {
+		return costEdit
+	}
+	return 2 * costEdit
+}
+
+// Suggest returns the candidates closest to the word, if they are close enough to
+// be likely misspellings of it. Several candidates are returned if they are equally
+// close, in the order they are given. Returns nil if the word is one of the
+// candidates (ignoring the case) or if none of them is close enough.
+func Suggest(word string, candidates []string) []string {
+	best := threshold(word) + 1
+	suggestions := []string{}
+
+	for _, candidate := range candidates {
+		distance := Distance(word, candidate)
+		switch {
+		case distance == 0:
+			return nil
+		case distance < best:
+			best = distance
+			suggestions = []string{candidate}
+		case distance == best && !slices.Contains(suggestions, candidate):
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return suggestions
+}
+
+// DidYouMean renders the suggestions as a question to append to an error message,
+// e.g. `did you mean "feat"?` or `did you mean "feat" or "fix"?`. Returns an empty
+// string if there are no suggestions.
+func DidYouMean(suggestions []string) string {
+	quoted := []string{}
+	for _, s := range suggestions {
+		quoted = append(quoted, `"`+s+`"`)
+	}
+
+	switch len(quoted) {
+	case 0:
+		return ""
+	case 1:
+		return "did you mean " + quoted[0] + "?"
+	}
+	last := len(quoted) - 1
+	return "did you mean " + strings.Join(quoted[:last], ", ") + " or " +
+		quoted[last] + "?"
+}
@@ -0,0 +1,79 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+var types = []string{
+	"build",
+	"ci",
+	"docs",
+	"feat",
+	"fix",
+	"perf",
+	"refactor",
+	"style",
+	"test",
+	"chore",
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"feat", "feat", 0},
+		{"Feat", "feat", 0},
+		{"feet", "feat", 3},
+		{"feet", "test", 4}, // "t" is next to "f" and "s" is next to "e"
+		{"fwat", "feat", 2},
+		{"refactr", "refactor", 3},
+		{"fxi", "fix", 2},
+		{"Closses", "Closes", 3},
+		{"", "ci", 6},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"feet", []string{"feat"}},
+		{"refactr", []string{"refactor"}},
+		{"fxi", []string{"fix"}},
+		{"dosc", []string{"docs"}},
+		{"fest", []string{"feat", "test"}},
+		{"feat", nil},
+		{"feature", nil},
+		{"banana", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Suggest(tt.word, types); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	tests := map[string][]string{
+		"":                                    nil,
+		`did you mean "feat"?`:                {"feat"},
+		`did you mean "feat" or "test"?`:      {"feat", "test"},
+		`did you mean "ci", "fix" or "perf"?`: {"ci", "fix", "perf"},
+	}
+
+	for want, suggestions := range tests {
+		if got := DidYouMean(suggestions); got != want {
+			t.Errorf("DidYouMean(%q) = %q, want %q", suggestions, got, want)
+		}
+	}
+}
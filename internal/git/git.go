@@ -0,0 +1,228 @@
+// Package git provides a thin wrapper around the "git" executable for the commands
+// which need to interact with a Git repository (making commits, listing revisions and
+// so on).
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// Repo represents a Git repository (or a working tree inside of it) located on the
+// local filesystem.
+type Repo struct {
+	dir string
+}
+
+// The NewRepo() constructor creates an instance of Repo for the repository at dir. An
+// empty dir refers to the current working directory.
+func NewRepo(dir string) *Repo {
+	return &Repo{dir: dir}
+}
+
+// run invokes git with the given arguments inside the repository and returns its
+// STDOUT with the trailing newline removed. The STDERR output of git is included in
+// the returned error on failure.
+func (r *Repo) run(stdin io.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// Commit records a new commit with the given message. Any extra arguments are passed
+// verbatim to "git commit" (e.g. "--amend" or "--signoff").
+func (r *Repo) Commit(message string, args ...string) error {
+	args = append([]string{"commit", "--file", "-", "--cleanup", "whitespace"}, args...)
+	_, err := r.run(strings.NewReader(message), args...)
+	return err
+}
+
+// IsZeroSHA reports whether sha is the all-zeros object name Git uses to denote a
+// non-existent object, e.g. the old value of a newly created ref.
+func IsZeroSHA(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
+
+// RevList returns the commit hashes printed by "git rev-list" for the given arguments.
+func (r *Repo) RevList(args ...string) ([]string, error) {
+	out, err := r.run(nil, append([]string{"rev-list"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// RevParse resolves rev (e.g. "HEAD" or a branch name) into the full object name.
+func (r *Repo) RevParse(rev string) (string, error) {
+	return r.run(nil, "rev-parse", "--verify", "--end-of-options", rev)
+}
+
+// HasCommit reports whether the object named by rev exists in the repository and is a
+// commit.
+func (r *Repo) HasCommit(rev string) bool {
+	_, err := r.run(nil, "cat-file", "-e", rev+"^{commit}")
+	return err == nil
+}
+
+// Message returns the raw commit message of the commit named by rev.
+func (r *Repo) Message(rev string) (string, error) {
+	return r.run(nil, "log", "-1", "--format=%B", rev)
+}
+
+// Author returns the identity ("Name <email>") of the author of the commit named by
+// rev.
+func (r *Repo) Author(rev string) (string, error) {
+	return r.run(nil, "log", "-1", "--format=%an <%ae>", rev)
+}
+
+// NextAuthor returns the identity ("Name <email>") the next commit is authored by,
+// i.e. the configured user unless overridden by the GIT_AUTHOR_NAME and
+// GIT_AUTHOR_EMAIL environment variables.
+func (r *Repo) NextAuthor() (string, error) {
+	out, err := r.run(nil, "var", "GIT_AUTHOR_IDENT")
+	if err != nil {
+		return "", err
+	}
+
+	// Drop the timestamp following the e-mail address
+	if end := strings.LastIndex(out, ">"); end >= 0 {
+		out = out[:end+1]
+	}
+	return out, nil
+}
+
+// User returns the identity ("Name <email>") configured with the "user.name" and
+// "user.email" settings. Returns an error if either of them is not set.
+func (r *Repo) User() (string, error) {
+	name, err := r.run(nil, "config", "--get", "user.name")
+	if err != nil {
+		return "", fmt.Errorf("user.name is not configured")
+	}
+	email, err := r.run(nil, "config", "--get", "user.email")
+	if err != nil {
+		return "", fmt.Errorf("user.email is not configured")
+	}
+	return fmt.Sprintf("%s <%s>", name, email), nil
+}
+
+// Subjects returns the subject lines of (at most limit) most recent commits reachable
+// from HEAD, newest first.
+func (r *Repo) Subjects(limit int) ([]string, error) {
+	out, err := r.run(nil, "log", "--format=%s", fmt.Sprintf("--max-count=%d", limit))
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// MergeBase returns the best common ancestor of the commits named by a and b.
+func (r *Repo) MergeBase(a, b string) (string, error) {
+	return r.run(nil, "merge-base", a, b)
+}
+
+// IsShallow reports whether the repository is a shallow clone, i.e. whether part of
+// its history is missing.
+func (r *Repo) IsShallow() (bool, error) {
+	out, err := r.run(nil, "rev-parse", "--is-shallow-repository")
+	if err != nil {
+		return false, err
+	}
+	return out == "true", nil
+}
+
+// CurrentBranch returns the short name of the branch checked out in the working tree.
+// Returns an error if HEAD is detached.
+func (r *Repo) CurrentBranch() (string, error) {
+	return r.run(nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+}
+
+// TrackedFiles returns the paths (relative to the root of the repository) of all the
+// files tracked in the index.
+func (r *Repo) TrackedFiles() ([]string, error) {
+	out, err := r.run(nil, "ls-files", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// StagedFiles returns the paths (relative to the root of the repository) of the files
+// changed in the index compared to HEAD, i.e. the files the next commit changes.
+func (r *Repo) StagedFiles() ([]string, error) {
+	out, err := r.run(nil, "diff", "--cached", "--name-only", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ChangedFiles returns the paths (relative to the root of the repository) of the files
+// changed by the commit named by rev compared to its first parent.
+func (r *Repo) ChangedFiles(rev string) ([]string, error) {
+	out, err := r.run(
+		nil,
+		"diff-tree", "--no-commit-id", "--name-only", "-r", "--no-renames", "--root",
+		rev,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return []string{}, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// Change is a file changed by a commit.
+type Change struct {
+	Status string // "A" (added), "M" (modified), "D" (deleted) or "T" (type changed)
+	Path   string // Path relative to the root of the repository
+}
+
+// StagedChanges returns the files changed in the index compared to HEAD along with
+// how they were changed.
+func (r *Repo) StagedChanges() ([]Change, error) {
+	out, err := r.run(nil, "diff", "--cached", "--name-status", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	for _, line := range strings.Split(out, "\n") {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		changes = append(changes, Change{Status: status, Path: path})
+	}
+	return changes, nil
+}
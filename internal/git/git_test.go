@@ -0,0 +1,296 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/git/gittest"
+)
+
+func TestRepo_Commit(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	message := "feat(git): add commit support\n\nRefs: #12"
+	if err := repo.Commit(message, "--allow-empty"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := repo.run(nil, "log", "-1", "--format=%B")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != message {
+		t.Errorf("commit message = %q, want %q", got, message)
+	}
+}
+
+func TestRepo_Commit_Error(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	// Nothing is staged, so git refuses to create the commit
+	if err := repo.Commit("fix: nothing to see here"); err == nil {
+		t.Error("expected error when committing without changes, got nil")
+	}
+}
+
+// commit records an empty commit with the given message and returns its hash.
+func commit(t *testing.T, repo *Repo, message string) string {
+	t.Helper()
+
+	if err := repo.Commit(message, "--allow-empty"); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	sha, err := repo.RevParse("HEAD")
+	if err != nil {
+		t.Fatalf("failed to resolve HEAD: %v", err)
+	}
+	return sha
+}
+
+func TestIsZeroSHA(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"0000000000000000000000000000000000000000", true},
+		{strings.Repeat("0", 64), true},
+		{"a94a8fe5ccb19ba61c4c0873d391e987982fbbd3", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsZeroSHA(tt.input); got != tt.want {
+			t.Errorf("IsZeroSHA(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRepo_RevList(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	first := commit(t, repo, "feat: first")
+	second := commit(t, repo, "feat: second")
+
+	got, err := repo.RevList(first + ".." + second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != second {
+		t.Errorf("RevList() = %v, want [%s]", got, second)
+	}
+
+	got, err = repo.RevList(second + ".." + second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("RevList() = %v, want []", got)
+	}
+}
+
+func TestRepo_HasCommitAndMessage(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	sha := commit(t, repo, "fix: handle the thing\n\nWith a body.")
+
+	if !repo.HasCommit(sha) {
+		t.Errorf("expected commit %s to exist", sha)
+	}
+	if repo.HasCommit(strings.Repeat("1", 40)) {
+		t.Error("expected unknown commit to not exist")
+	}
+
+	got, err := repo.Message(sha)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "fix: handle the thing\n\nWith a body." {
+		t.Errorf("Message() = %q", got)
+	}
+}
+
+func TestRepo_Subjects(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	commit(t, repo, "feat(git): first\n\nBody.")
+	commit(t, repo, "fix(lsp): second")
+	commit(t, repo, "docs: third")
+
+	got, err := repo.Subjects(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"docs: third", "fix(lsp): second"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Subjects() = %q, want %q", got, want)
+	}
+}
+
+func TestRepo_MergeBase(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	base := commit(t, repo, "feat: base")
+	if _, err := repo.run(nil, "checkout", "--quiet", "-b", "topic"); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	commit(t, repo, "feat: topic")
+	if _, err := repo.run(nil, "checkout", "--quiet", "main"); err != nil {
+		t.Fatalf("failed to switch branch: %v", err)
+	}
+	commit(t, repo, "feat: main")
+
+	got, err := repo.MergeBase("main", "topic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != base {
+		t.Errorf("MergeBase() = %s, want %s", got, base)
+	}
+}
+
+func TestRepo_IsShallow(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+	commit(t, repo, "feat: first")
+	commit(t, repo, "feat: second")
+
+	if shallow, err := repo.IsShallow(); err != nil || shallow {
+		t.Errorf("IsShallow() = (%v, %v), want (false, nil)", shallow, err)
+	}
+
+	dir := t.TempDir()
+	if _, err := repo.run(nil, "clone", "--quiet", "--depth", "1",
+		"file://"+repo.dir, dir); err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+
+	clone := NewRepo(dir)
+	if shallow, err := clone.IsShallow(); err != nil || !shallow {
+		t.Errorf("IsShallow() = (%v, %v), want (true, nil)", shallow, err)
+	}
+}
+
+func TestRepo_CurrentBranch(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+	sha := commit(t, repo, "feat: first")
+
+	if got, err := repo.CurrentBranch(); err != nil || got != "main" {
+		t.Errorf("CurrentBranch() = (%q, %v), want (\"main\", nil)", got, err)
+	}
+
+	if _, err := repo.run(nil, "checkout", "--quiet", "--detach", sha); err != nil {
+		t.Fatalf("failed to detach HEAD: %v", err)
+	}
+	if _, err := repo.CurrentBranch(); err == nil {
+		t.Error("expected error for a detached HEAD, got nil")
+	}
+}
+
+func TestRepo_StagedFiles(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	for _, name := range []string{"README.md", "main.go"} {
+		if err := os.WriteFile(filepath.Join(repo.dir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if _, err := repo.run(nil, "add", "README.md"); err != nil {
+		t.Fatalf("failed to stage README.md: %v", err)
+	}
+
+	got, err := repo.StagedFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != "README.md" {
+		t.Errorf("StagedFiles() = %q, want [README.md]", got)
+	}
+}
+
+func TestRepo_StagedChanges(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	for _, name := range []string{"README.md", "main.go"} {
+		if err := os.WriteFile(filepath.Join(repo.dir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if _, err := repo.run(nil, "add", "README.md", "main.go"); err != nil {
+		t.Fatalf("failed to stage the files: %v", err)
+	}
+	commit(t, repo, "feat: first")
+
+	path := filepath.Join(repo.dir, "main.go")
+	if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+		t.Fatalf("failed to write main.go: %v", err)
+	}
+	for _, args := range [][]string{
+		{"rm", "--quiet", "README.md"},
+		{"add", "main.go"},
+	} {
+		if _, err := repo.run(nil, args...); err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+	}
+
+	got, err := repo.StagedChanges()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Change{{Status: "D", Path: "README.md"}, {Status: "M", Path: "main.go"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StagedChanges() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRepo_ChangedFiles(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+
+	for _, name := range []string{"README.md", "main.go"} {
+		if err := os.WriteFile(filepath.Join(repo.dir, name), nil, 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		if _, err := repo.run(nil, "add", name); err != nil {
+			t.Fatalf("failed to stage %s: %v", name, err)
+		}
+		commit(t, repo, "feat: add "+name)
+	}
+
+	// The files of the root commit are compared with an empty tree
+	for rev, want := range map[string]string{"HEAD~1": "README.md", "HEAD": "main.go"} {
+		got, err := repo.ChangedFiles(rev)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("ChangedFiles(%s) = %q, want [%s]", rev, got, want)
+		}
+	}
+}
+
+func TestRepo_Identities(t *testing.T) {
+	repo := NewRepo(gittest.NewRepo(t))
+	sha := commit(t, repo, "fix: handle the thing")
+
+	want := "Crisp Test <crisp@example.com>"
+	if got, err := repo.Author(sha); err != nil || got != want {
+		t.Errorf("Author() = %q, %v, want %q", got, err, want)
+	}
+	if got, err := repo.User(); err != nil || got != want {
+		t.Errorf("User() = %q, %v, want %q", got, err, want)
+	}
+	if got, err := repo.NextAuthor(); err != nil || got != want {
+		t.Errorf("NextAuthor() = %q, %v, want %q", got, err, want)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "Jane Doe")
+	t.Setenv("GIT_AUTHOR_EMAIL", "jane@example.com")
+	want = "Jane Doe <jane@example.com>"
+	if got, err := repo.NextAuthor(); err != nil || got != want {
+		t.Errorf("NextAuthor() = %q, %v, want %q", got, err, want)
+	}
+}
@@ -0,0 +1,48 @@
+// Package gittest provides the helpers shared by the tests which run Git against
+// temporary repositories.
+package gittest
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// Run runs git with the given arguments inside dir and returns its trimmed output. The
+// test fails if git exits with an error.
+func Run(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// NewRepo initialises an empty Git repository with a committer identity configured in
+// a temporary directory, and returns the directory. The arguments are passed on to
+// "git init", e.g. "--bare".
+func NewRepo(t testing.TB, args ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	initArgs := []string{"init", "--quiet", "--initial-branch", "main"}
+	Run(t, dir, append(initArgs, args...)...)
+	Run(t, dir, "config", "user.name", "Crisp Test")
+	Run(t, dir, "config", "user.email", "crisp@example.com")
+	Run(t, dir, "config", "commit.gpgsign", "false")
+
+	return dir
+}
+
+// Commit records an empty commit with the given message in the repository at dir and
+// returns its hash.
+func Commit(t testing.TB, dir, message string) string {
+	t.Helper()
+
+	Run(t, dir, "commit", "--quiet", "--allow-empty", "--message", message)
+	return Run(t, dir, "rev-parse", "HEAD")
+}
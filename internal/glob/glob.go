@@ -0,0 +1,59 @@
+// Package glob matches slash-separated paths against glob patterns like the ones of
+// ".gitignore" files, e.g. "internal/**/*_test.go".
+package glob
+
+import (
+	"path"
+	"strings"
+)
+
+// Match reports whether the slash-separated name matches the pattern. In addition to
+// the syntax of path.Match, a "**" path segment matches zero or more directories and
+// a pattern without a slash matches the base name of the path (e.g. "*.md" matches
+// "docs/index.md"). Malformed patterns match nothing.
+func Match(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+
+	return matchSegments(
+		strings.Split(strings.TrimPrefix(pattern, "/"), "/"),
+		strings.Split(name, "/"),
+	)
+}
+
+// MatchAny reports whether the name matches any of the patterns.
+func MatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches the segments of a path against the segments of a pattern.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try to match the rest of the pattern at every remaining depth
+			for idx := 0; idx <= len(name); idx++ {
+				if matchSegments(pattern[1:], name[idx:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
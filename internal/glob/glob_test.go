@@ -0,0 +1,41 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/parser/**", "internal/parser/parser.go", true},
+		{"internal/parser/**", "internal/parser/testdata/a.txt", true},
+		{"internal/parser/**", "internal/parsers/parser.go", false},
+		{"**/*_test.go", "internal/git/git_test.go", true},
+		{"**/*_test.go", "main_test.go", true},
+		{"**/*_test.go", "internal/git/git.go", false},
+		{"*.md", "docs/src/content/docs/index.md", true},
+		{"*.md", "README.md", true},
+		{"docs/*.md", "docs/src/index.md", false},
+		{"/go.mod", "go.mod", true},
+		{".github/workflows/*", ".github/workflows/ci.yml", true},
+		{"cmd/[", "cmd/[", false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"*.md", "docs/**"}
+
+	if !MatchAny(patterns, "docs/astro.config.mjs") {
+		t.Error("expected docs/astro.config.mjs to match")
+	}
+	if MatchAny(patterns, "cmd/root.go") {
+		t.Error("expected cmd/root.go to not match")
+	}
+}
@@ -0,0 +1,148 @@
+// Package hook implements the protocols Git uses to pass information to the client
+// and server side hooks, and computes the commits which have to be linted for them.
+package hook
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/git"
+)
+
+// PushUpdate is a single ref update passed to the pre-push hook on STDIN as
+// "<local ref> <local sha> <remote ref> <remote sha>".
+type PushUpdate struct {
+	LocalRef, LocalSHA, RemoteRef, RemoteSHA string
+}
+
+// ParsePrePush parses the lines written by Git to the STDIN of the pre-push hook.
+// Blank lines are ignored and an error is returned for malformed lines.
+func ParsePrePush(r io.Reader) ([]PushUpdate, error) {
+	updates := []PushUpdate{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("malformed pre-push line: %q", line)
+		}
+
+		updates = append(updates, PushUpdate{
+			LocalRef:  fields[0],
+			LocalSHA:  fields[1],
+			RemoteRef: fields[2],
+			RemoteSHA: fields[3],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning pre-push input: %w", err)
+	}
+
+	return updates, nil
+}
+
+// IsDeletion reports whether the update deletes the remote ref.
+func (u PushUpdate) IsDeletion() bool {
+	return git.IsZeroSHA(u.LocalSHA)
+}
+
+// Commits returns the (non-merge) commits which are about to be pushed by the update,
+// newest first.
+//
+// For an existing remote ref these are the commits between the remote and the local
+// object. For a new remote ref (or when the remote object is not available locally)
+// these are the commits not reachable from any of the remote-tracking refs of remote,
+// or of all the remotes if remote is a URL. Only the pushed commit is returned if there
+// are no such refs, rather than the whole history. Deletions push no commits.
+func (u PushUpdate) Commits(repo *git.Repo, remote string) ([]string, error) {
+	if u.IsDeletion() {
+		return []string{}, nil
+	}
+
+	if !git.IsZeroSHA(u.RemoteSHA) && repo.HasCommit(u.RemoteSHA) {
+		return repo.RevList("--no-merges", u.RemoteSHA+".."+u.LocalSHA)
+	}
+
+	exclude := "--remotes"
+	if remote != "" && !isURL(remote) {
+		exclude = "--remotes=" + remote
+	}
+
+	known, err := repo.RevList("--max-count=1", exclude)
+	if err != nil {
+		return nil, err
+	}
+	if len(known) == 0 {
+		return repo.RevList("--no-merges", "--max-count=1", u.LocalSHA)
+	}
+	return repo.RevList("--no-merges", u.LocalSHA, "--not", exclude)
+}
+
+// isURL reports whether the remote passed to the pre-push hook is the URL (or the
+// path) of the repository pushed to rather than the name of a configured remote,
+// e.g. in "git push https://example.com/repo.git main".
+func isURL(remote string) bool {
+	return strings.Contains(remote, ":") || strings.HasPrefix(remote, "/") ||
+		strings.HasPrefix(remote, ".") || strings.HasPrefix(remote, "~")
+}
+
+// RefUpdate is a single ref update passed to the pre-receive hook on STDIN as
+// "<old sha> <new sha> <ref>" (or to the update hook as its arguments).
+type RefUpdate struct {
+	OldSHA, NewSHA, Ref string
+}
+
+// ParsePreReceive parses the lines written by Git to the STDIN of the pre-receive
+// hook. Blank lines are ignored and an error is returned for malformed lines.
+func ParsePreReceive(r io.Reader) ([]RefUpdate, error) {
+	updates := []RefUpdate{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("malformed pre-receive line: %q", line)
+		}
+
+		updates = append(updates, RefUpdate{
+			OldSHA: fields[0],
+			NewSHA: fields[1],
+			Ref:    fields[2],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error scanning pre-receive input: %w", err)
+	}
+
+	return updates, nil
+}
+
+// IsDeletion reports whether the update deletes the ref.
+func (u RefUpdate) IsDeletion() bool {
+	return git.IsZeroSHA(u.NewSHA)
+}
+
+// Commits returns the (non-merge) commits introduced by the update, newest first.
+//
+// Only the commits not reachable from any of the existing refs of the repository are
+// returned, so commits which were already accepted (e.g. when creating a new branch
+// off an existing one) are not linted again. Deletions introduce no commits.
+func (u RefUpdate) Commits(repo *git.Repo) ([]string, error) {
+	if u.IsDeletion() {
+		return []string{}, nil
+	}
+
+	return repo.RevList("--no-merges", u.NewSHA, "--not", "--all")
+}
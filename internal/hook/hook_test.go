@@ -0,0 +1,207 @@
+package hook
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/git/gittest"
+)
+
+// zeroSHA is the object name Git uses for non-existent objects.
+var zeroSHA = strings.Repeat("0", 40)
+
+func TestParsePrePush(t *testing.T) {
+	input := "refs/heads/main 1111 refs/heads/main 2222\n\n" +
+		"refs/heads/topic 3333 refs/heads/topic " + zeroSHA + "\n"
+
+	got, err := ParsePrePush(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []PushUpdate{
+		{"refs/heads/main", "1111", "refs/heads/main", "2222"},
+		{"refs/heads/topic", "3333", "refs/heads/topic", zeroSHA},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePrePush() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParsePrePush(strings.NewReader("refs/heads/main 1111\n")); err == nil {
+		t.Error("expected error for malformed line, got nil")
+	}
+}
+
+func TestPushUpdate_Commits(t *testing.T) {
+	dir := gittest.NewRepo(t)
+	repo := git.NewRepo(dir)
+
+	base := gittest.Commit(t, dir, "feat: base")
+	gittest.Run(t, dir, "update-ref", "refs/remotes/origin/main", base)
+	first := gittest.Commit(t, dir, "feat: first")
+	second := gittest.Commit(t, dir, "fix: second")
+
+	tests := []struct {
+		name   string
+		update PushUpdate
+		want   []string
+	}{
+		{
+			name:   "existing remote ref",
+			update: PushUpdate{"refs/heads/main", second, "refs/heads/main", base},
+			want:   []string{second, first},
+		},
+		{
+			name:   "new remote ref",
+			update: PushUpdate{"refs/heads/main", second, "refs/heads/topic", zeroSHA},
+			want:   []string{second, first},
+		},
+		{
+			name: "unknown remote object",
+			update: PushUpdate{
+				"refs/heads/main",
+				second,
+				"refs/heads/main",
+				strings.Repeat("1", 40),
+			},
+			want: []string{second, first},
+		},
+		{
+			name:   "deletion",
+			update: PushUpdate{"(delete)", zeroSHA, "refs/heads/topic", second},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.update.Commits(repo, "origin")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Commits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushUpdate_Commits_URL(t *testing.T) {
+	dir := gittest.NewRepo(t)
+	repo := git.NewRepo(dir)
+
+	base := gittest.Commit(t, dir, "feat: base")
+	gittest.Run(t, dir, "update-ref", "refs/remotes/origin/main", base)
+	first := gittest.Commit(t, dir, "feat: first")
+	update := PushUpdate{"refs/heads/main", first, "refs/heads/topic", zeroSHA}
+
+	// The commits known to any of the remotes are skipped when pushing to a URL
+	for _, remote := range []string{
+		"https://example.com/repo.git",
+		"git@example.com:repo.git",
+		"../repo.git",
+	} {
+		got, err := update.Commits(repo, remote)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", remote, err)
+		}
+		if !reflect.DeepEqual(got, []string{first}) {
+			t.Errorf("%s: Commits() = %v, want %v", remote, got, []string{first})
+		}
+	}
+
+	// Without any remote-tracking ref, only the pushed commit is returned
+	dir = gittest.NewRepo(t)
+	repo = git.NewRepo(dir)
+	gittest.Commit(t, dir, "feat: base")
+	second := gittest.Commit(t, dir, "fix: second")
+	update = PushUpdate{"refs/heads/main", second, "refs/heads/main", zeroSHA}
+
+	got, err := update.Commits(repo, "https://example.com/repo.git")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{second}) {
+		t.Errorf("Commits() = %v, want %v", got, []string{second})
+	}
+}
+
+func TestParsePreReceive(t *testing.T) {
+	input := zeroSHA + " 1111 refs/heads/topic\n\n2222 " + zeroSHA + " refs/heads/old\n"
+
+	got, err := ParsePreReceive(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []RefUpdate{
+		{zeroSHA, "1111", "refs/heads/topic"},
+		{"2222", zeroSHA, "refs/heads/old"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePreReceive() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParsePreReceive(strings.NewReader("1111 2222\n")); err == nil {
+		t.Error("expected error for malformed line, got nil")
+	}
+}
+
+func TestRefUpdate_Commits(t *testing.T) {
+	bareDir := gittest.NewRepo(t, "--bare")
+	bare := git.NewRepo(bareDir)
+	workDir := gittest.NewRepo(t)
+	gittest.Run(t, workDir, "remote", "add", "origin", bareDir)
+
+	// Publish the base commit on the main branch of the bare repository
+	base := gittest.Commit(t, workDir, "feat: base")
+	gittest.Run(t, workDir, "push", "--quiet", "origin", "main")
+
+	// Transfer the new commits without updating any branch, mimicking the state of
+	// the bare repository while the pre-receive hook runs
+	first := gittest.Commit(t, workDir, "feat: first")
+	second := gittest.Commit(t, workDir, "fix: second")
+	gittest.Run(t, workDir, "push", "--quiet", "origin", "main:refs/crisp/incoming")
+	gittest.Run(t, bareDir, "update-ref", "-d", "refs/crisp/incoming")
+
+	tests := []struct {
+		name   string
+		update RefUpdate
+		want   []string
+	}{
+		{
+			name:   "existing branch",
+			update: RefUpdate{base, second, "refs/heads/main"},
+			want:   []string{second, first},
+		},
+		{
+			name:   "new branch",
+			update: RefUpdate{zeroSHA, second, "refs/heads/topic"},
+			want:   []string{second, first},
+		},
+		{
+			name:   "new branch of an existing commit",
+			update: RefUpdate{zeroSHA, base, "refs/heads/topic"},
+			want:   []string{},
+		},
+		{
+			name:   "deletion",
+			update: RefUpdate{base, zeroSHA, "refs/heads/main"},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.update.Commits(bare)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Commits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
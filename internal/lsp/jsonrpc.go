@@ -0,0 +1,107 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// message is an incoming JSON-RPC 2.0 request or notification. Notifications carry no
+// ID and must not be replied to.
+type message struct {
+	ID     *json.RawMessage `json:"id,omitempty"`
+	Method string           `json:"method"`
+	Params json.RawMessage  `json:"params,omitempty"`
+}
+
+// responseError is the error object of a failed JSON-RPC request.
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// conn reads and writes JSON-RPC messages framed with the "Content-Length" header as
+// specified by the Language Server Protocol.
+type conn struct {
+	r  *bufio.Reader
+	w  io.Writer
+	mu sync.Mutex // Serialises the writes of messages
+}
+
+// newConn creates a connection reading from r and writing to w.
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+// read reads the next message from the connection.
+func (c *conn) read() (*message, error) {
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	contentLength := header.Get("Content-Length")
+	length, err := strconv.Atoi(contentLength)
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", contentLength)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, err
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, &responseError{Code: codeParseError, Message: err.Error()}
+	}
+	return &msg, nil
+}
+
+// reply sends the result of the request with the given ID.
+func (c *conn) reply(id *json.RawMessage, result any) error {
+	return c.write(map[string]any{"jsonrpc": "2.0", "id": id, "result": result})
+}
+
+// replyError sends the error of the request with the given ID.
+func (c *conn) replyError(id *json.RawMessage, err *responseError) error {
+	return c.write(map[string]any{"jsonrpc": "2.0", "id": id, "error": err})
+}
+
+// notify sends a notification with the given method and parameters.
+func (c *conn) notify(method string, params any) error {
+	return c.write(map[string]any{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+// write encodes and writes a single message to the connection.
+func (c *conn) write(msg map[string]any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// Error implements the error interface for responseError.
+func (e *responseError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,464 @@
+// Package lsp implements a Language Server Protocol server for Git commit messages
+// ("gitcommit" buffers). It publishes the diagnostics of the validator as the message
+// is typed, offers code actions for the fixable violations, completes the types,
+// scopes and footer keys and shows the documentation of the rules on hover.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// errExitWithoutShutdown is returned by Run if the client asked the server to exit
+// without shutting it down first.
+var errExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// server is the state of the language server. The messages are handled one at a time
+// in the order they are received, hence the state needs no synchronisation.
+type server struct {
+	conn      *conn
+	version   string
+	scopes    []string
+	documents map[string]string
+	root      string // The directory of the workspace, if any
+	shutdown  bool
+}
+
+// The NewServer() constructor creates a language server communicating over r and w
+// (usually STDIN and STDOUT). The scopes are offered as completions for the scope of
+// the commit message header.
+func NewServer(r io.Reader, w io.Writer, version string, scopes []string) *server {
+	return &server{
+		conn:      newConn(r, w),
+		version:   version,
+		scopes:    scopes,
+		documents: map[string]string{},
+	}
+}
+
+// Run handles the incoming messages until the client asks the server to exit or closes
+// the connection.
+func (s *server) Run() error {
+	for {
+		msg, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var rpcErr *responseError
+		if errors.As(err, &rpcErr) {
+			if err := s.conn.replyError(nil, rpcErr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a single message to its handler and replies to requests.
+func (s *server) handle(msg *message) error {
+	var (
+		result any
+		err    error
+	)
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.root, _ = uriPath(params.RootURI)
+			result = s.initialize()
+		}
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			err = s.update(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			// Only full document synchronisation is supported, so the last change
+			// holds the entire content of the document
+			if n := len(params.ContentChanges); n > 0 {
+				err = s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+			}
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			delete(s.documents, params.TextDocument.URI)
+			err = s.publish(params.TextDocument.URI, []diagnostic{})
+		}
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.codeActions(params)
+		}
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.completion(params)
+		}
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.hover(params)
+		}
+	default:
+		if msg.ID != nil {
+			return s.conn.replyError(msg.ID, &responseError{
+				Code:    codeMethodNotFound,
+				Message: "method not found: " + msg.Method,
+			})
+		}
+		// Unknown notifications (e.g. "initialized") are ignored
+		return nil
+	}
+
+	// Notifications are never replied to, not even on failure
+	if msg.ID == nil {
+		return nil
+	}
+	if err != nil {
+		return s.conn.replyError(msg.ID, &responseError{
+			Code:    codeInvalidParams,
+			Message: err.Error(),
+		})
+	}
+	return s.conn.reply(msg.ID, result)
+}
+
+// initialize returns the capabilities of the server.
+func (s *server) initialize() any {
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync":   1,
+			"codeActionProvider": true,
+			"hoverProvider":      true,
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"("},
+			},
+		},
+		"serverInfo": map[string]string{"name": "crisp", "version": s.version},
+	}
+}
+
+// update stores the new content of a document and publishes its diagnostics.
+func (s *server) update(uri, text string) error {
+	s.documents[uri] = text
+
+	lines := strings.Split(text, "\n")
+	diagnostics := []diagnostic{}
+	for _, d := range s.lint(uri, text) {
+		diagnostics = append(diagnostics, toDiagnostic(lines, d))
+	}
+
+	return s.publish(uri, diagnostics)
+}
+
+// publish sends the diagnostics of a document to the client.
+func (s *server) publish(uri string, diagnostics []diagnostic) error {
+	return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics,
+	})
+}
+
+// lint parses and validates the text of a document with the configuration applying to
+// it. A header which can not be parsed is reported as a diagnostic of its own, while an
+// empty message is not reported at all since the user has not started typing yet.
+func (s *server) lint(uri, text string) []validator.Diagnostic {
+	text = parser.StripComments(text)
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	// An invalid configuration file is reported instead of linting the message with
+	// settings the hooks would reject as well
+	cfg, err := s.config(uri)
+	if err != nil {
+		return []validator.Diagnostic{{
+			Rule:     "config",
+			Severity: validator.SeverityError,
+			Message:  "invalid configuration: " + err.Error(),
+			Line:     1,
+		}}
+	}
+
+	header, _, _ := strings.Cut(text, "\n")
+	msg, err := parser.ParseCommitMessage(text)
+	if err != nil {
+		return []validator.Diagnostic{{
+			Rule:     "header",
+			Severity: validator.SeverityError,
+			Message:  strings.TrimPrefix(err.Error(), "error: "),
+			Line:     1,
+			Span:     &parser.Span{Start: 0, End: len(header)},
+		}}
+	}
+
+	return validator.NewValidatorWithConfig(cfg).Lint(msg)
+}
+
+// config loads the configuration file applying to the document, i.e. the one of the
+// repository holding it (found from the .git/COMMIT_EDITMSG file up). The one of the
+// workspace is used for the documents which are not files.
+func (s *server) config(uri string) (*config.Config, error) {
+	dir := s.root
+	if path, ok := uriPath(uri); ok {
+		dir = filepath.Dir(path)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	return config.Load(dir)
+}
+
+// uriPath returns the path of a "file://" URI. Returns false for the other URIs.
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// toDiagnostic converts a diagnostic of the validator into its protocol counterpart.
+// Diagnostics without a location are reported on the header line.
+func toDiagnostic(lines []string, d validator.Diagnostic) diagnostic {
+	severity := severityError
+	if d.Severity == validator.SeverityWarning {
+		severity = severityWarning
+	}
+
+	return diagnostic{
+		Range:    diagnosticRange(lines, d),
+		Severity: severity,
+		Code:     d.Rule,
+		Source:   "crisp",
+		Message:  d.Message,
+	}
+}
+
+// diagnosticRange returns the range of the document covered by the diagnostic.
+func diagnosticRange(lines []string, d validator.Diagnostic) Range {
+	line := max(d.Line-1, 0)
+	if line >= len(lines) {
+		return Range{Start: Position{Line: line}, End: Position{Line: line}}
+	}
+
+	span := parser.Span{Start: 0, End: len(lines[line])}
+	if d.Span != nil {
+		span = *d.Span
+	}
+	return spanRange(lines, line, span)
+}
+
+// spanRange converts a byte span of a (zero-based) line into a range of the document.
+func spanRange(lines []string, line int, span parser.Span) Range {
+	text := ""
+	if line < len(lines) {
+		text = lines[line]
+	}
+
+	return Range{
+		Start: Position{Line: line, Character: utf16Offset(text, span.Start)},
+		End:   Position{Line: line, Character: utf16Offset(text, span.End)},
+	}
+}
+
+// codeActions returns the quick fixes of the fixable diagnostics within the range.
+func (s *server) codeActions(params codeActionParams) []codeAction {
+	uri := params.TextDocument.URI
+	text, ok := s.documents[uri]
+	if !ok {
+		return []codeAction{}
+	}
+
+	lines := strings.Split(text, "\n")
+	actions := []codeAction{}
+	for _, d := range s.lint(uri, text) {
+		if d.Fix == nil {
+			continue
+		}
+
+		diag := toDiagnostic(lines, d)
+		if !diag.Range.overlaps(params.Range) {
+			continue
+		}
+
+		actions = append(actions, codeAction{
+			Title:       d.Fix.Title,
+			Kind:        "quickfix",
+			Diagnostics: []diagnostic{diag},
+			IsPreferred: true,
+			Edit: workspaceEdit{
+				Changes: map[string][]textEdit{uri: {fixEdit(lines, *d.Fix)}},
+			},
+		})
+	}
+
+	return actions
+}
+
+// fixEdit converts a fix of the validator into a text edit of the document.
+func fixEdit(lines []string, fix validator.Fix) textEdit {
+	// Fixes past the end of the message append a new line
+	if fix.Line > len(lines) {
+		last := len(lines) - 1
+		character := utf16Offset(lines[last], len(lines[last]))
+		end := Position{Line: last, Character: character}
+		return textEdit{Range: Range{Start: end, End: end}, NewText: "\n" + fix.NewText}
+	}
+
+	return textEdit{Range: spanRange(lines, fix.Line-1, fix.Span), NewText: fix.NewText}
+}
+
+// completion returns the types or scopes when completing the header and the footer
+// and trailer keys when completing the start of any other line.
+func (s *server) completion(params textDocumentPositionParams) []completionItem {
+	text := s.documents[params.TextDocument.URI]
+	lines := strings.Split(text, "\n")
+	if params.Position.Line >= len(lines) {
+		return []completionItem{}
+	}
+
+	line := lines[params.Position.Line]
+	before := line[:byteOffset(line, params.Position.Character)]
+	items := []completionItem{}
+
+	switch {
+	case params.Position.Line == 0 && !strings.ContainsAny(before, "(:"):
+		for _, typ := range validator.Types() {
+			items = append(items, completionItem{
+				Label:  typ,
+				Kind:   completionKindKeyword,
+				Detail: "commit type",
+			})
+		}
+	case params.Position.Line == 0 && strings.Contains(before, "(") &&
+		!strings.ContainsAny(before, "):"):
+		for _, scope := range s.scopes {
+			items = append(items, completionItem{
+				Label:  scope,
+				Kind:   completionKindModule,
+				Detail: "commit scope",
+			})
+		}
+	case params.Position.Line > 0 && !strings.ContainsAny(before, ":#"):
+		for _, key := range parser.KnownFooters() {
+			items = append(items, completionItem{
+				Label:      key,
+				Kind:       completionKindProperty,
+				Detail:     "footer",
+				InsertText: key + ": ",
+			})
+		}
+		for _, key := range validator.Trailers() {
+			items = append(items, completionItem{
+				Label:      key,
+				Kind:       completionKindProperty,
+				Detail:     "trailer",
+				InsertText: key + ": ",
+			})
+		}
+	}
+
+	return items
+}
+
+// hover returns the documentation of the rule violated at the position, or of the rule
+// validating the header component at the position.
+func (s *server) hover(params textDocumentPositionParams) *hover {
+	text, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+
+	for _, d := range s.lint(params.TextDocument.URI, text) {
+		if r := diagnosticRange(lines, d); r.contains(params.Position) {
+			if doc, ok := ruleDoc(d.Rule); ok {
+				return &hover{Contents: doc, Range: &r}
+			}
+		}
+	}
+
+	// Fall back to the rule validating the header component under the cursor
+	msg, err := parser.ParseCommitMessage(parser.StripComments(text))
+	if err != nil || params.Position.Line != 0 {
+		return nil
+	}
+
+	components := []struct {
+		rule string
+		span *parser.Span
+	}{
+		{"type", &msg.Spans.Type},
+		{"scope-case", msg.Spans.Scope},
+		{"subject", &msg.Spans.Description},
+	}
+	for _, c := range components {
+		if c.span == nil {
+			continue
+		}
+		if r := spanRange(lines, 0, *c.span); r.contains(params.Position) {
+			doc, _ := ruleDoc(c.rule)
+			return &hover{Contents: doc, Range: &r}
+		}
+	}
+
+	return nil
+}
+
+// ruleDoc returns the documentation of the rule as Markdown.
+func ruleDoc(id string) (markupContent, bool) {
+	r, ok := validator.LookupRule(id)
+	if !ok {
+		return markupContent{}, false
+	}
+	return markupContent{Kind: "markdown", Value: r.Markdown()}, true
+}
+
+// utf16Offset converts a byte offset within s into an offset in UTF-16 code units.
+func utf16Offset(s string, offset int) int {
+	offset = min(max(offset, 0), len(s))
+	return len(utf16.Encode([]rune(s[:offset])))
+}
+
+// byteOffset converts an offset in UTF-16 code units within s into a byte offset.
+func byteOffset(s string, offset int) int {
+	units := 0
+	for idx, r := range s {
+		if units >= offset {
+			return idx
+		}
+		units += utf16.RuneLen(r)
+	}
+	return len(s)
+}
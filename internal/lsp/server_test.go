@@ -0,0 +1,352 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testClient drives a language server running in the background over pipes.
+type testClient struct {
+	t      *testing.T
+	w      io.WriteCloser
+	r      *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+// newTestClient starts a language server and returns a client connected to it.
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &testClient{
+		t:    t,
+		w:    clientW,
+		r:    bufio.NewReader(clientR),
+		done: make(chan error),
+	}
+	go func() {
+		err := NewServer(serverR, serverW, "test", []string{"lsp", "parser"}).Run()
+		serverW.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() { clientW.Close() })
+
+	return c
+}
+
+// send writes a message to the server.
+func (c *testClient) send(msg map[string]any) {
+	c.t.Helper()
+
+	msg["jsonrpc"] = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatalf("failed to encode message: %v", err)
+	}
+	_, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	if err != nil {
+		c.t.Fatalf("failed to write message: %v", err)
+	}
+}
+
+// receive reads the next message from the server.
+func (c *testClient) receive() map[string]json.RawMessage {
+	c.t.Helper()
+
+	length := 0
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			c.t.Fatalf("failed to read header: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if val, ok := strings.CutPrefix(line, "Content-Length: "); ok {
+			length, _ = strconv.Atoi(val)
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		c.t.Fatalf("failed to read body: %v", err)
+	}
+
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatalf("failed to decode message: %v", err)
+	}
+	return msg
+}
+
+// request sends a request and decodes the result of its response into result.
+func (c *testClient) request(method string, params any, result any) {
+	c.t.Helper()
+
+	c.nextID++
+	c.send(map[string]any{"id": c.nextID, "method": method, "params": params})
+
+	msg := c.receive()
+	if errMsg, ok := msg["error"]; ok {
+		c.t.Fatalf("%s failed: %s", method, errMsg)
+	}
+	if err := json.Unmarshal(msg["result"], result); err != nil {
+		c.t.Fatalf("failed to decode %s result: %v", method, err)
+	}
+}
+
+// open opens a document and returns the diagnostics published for it.
+func (c *testClient) open(uri, text string) []diagnostic {
+	c.t.Helper()
+
+	c.send(map[string]any{
+		"method": "textDocument/didOpen",
+		"params": map[string]any{
+			"textDocument": map[string]any{
+				"uri":        uri,
+				"languageId": "gitcommit",
+				"version":    1,
+				"text":       text,
+			},
+		},
+	})
+
+	return c.diagnostics()
+}
+
+// diagnostics reads the next diagnostics published by the server.
+func (c *testClient) diagnostics() []diagnostic {
+	c.t.Helper()
+
+	msg := c.receive()
+	var params publishDiagnosticsParams
+	if err := json.Unmarshal(msg["params"], &params); err != nil {
+		c.t.Fatalf("failed to decode diagnostics: %v", err)
+	}
+	return params.Diagnostics
+}
+
+// initialize performs the initialisation handshake.
+func (c *testClient) initialize() {
+	c.t.Helper()
+
+	var result map[string]any
+	c.request("initialize", map[string]any{}, &result)
+	if _, ok := result["capabilities"]; !ok {
+		c.t.Fatalf("expected capabilities in initialize result, got %v", result)
+	}
+	c.send(map[string]any{"method": "initialized", "params": map[string]any{}})
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	text := "Feat(lsp): add it\n\n# Please enter the commit message.\n"
+	diagnostics := c.open("file:///COMMIT_EDITMSG", text)
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d: %+v", len(diagnostics), diagnostics)
+	}
+	d := diagnostics[0]
+	want := Range{Start: Position{0, 0}, End: Position{0, 4}}
+	if d.Code != "type" || d.Range != want || d.Severity != severityError {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+
+	// Fixing the message clears the diagnostics
+	c.send(map[string]any{
+		"method": "textDocument/didChange",
+		"params": map[string]any{
+			"textDocument":   map[string]any{"uri": "file:///COMMIT_EDITMSG"},
+			"contentChanges": []map[string]any{{"text": "feat(lsp): add it\n"}},
+		},
+	})
+	if diagnostics := c.diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diagnostics)
+	}
+}
+
+func TestServer_Config(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	// The configuration of the repository holding the document applies to it
+	root := t.TempDir()
+	path := filepath.Join(root, ".crisp.json")
+	data := []byte(`{"rules": {"type": "warning"}}`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write configuration: %v", err)
+	}
+
+	uri := "file://" + filepath.ToSlash(filepath.Join(root, ".git", "COMMIT_EDITMSG"))
+	diagnostics := c.open(uri, "Feat(lsp): add it\n")
+	if len(diagnostics) != 1 || diagnostics[0].Severity != severityWarning {
+		t.Errorf("expected a warning for the type, got %+v", diagnostics)
+	}
+
+	// An invalid configuration is reported on its own
+	if err := os.WriteFile(path, []byte(`{`), 0o644); err != nil {
+		t.Fatalf("failed to write configuration: %v", err)
+	}
+	diagnostics = c.open(uri, "Feat(lsp): add it\n")
+	if len(diagnostics) != 1 || diagnostics[0].Code != "config" {
+		t.Errorf("expected a configuration diagnostic, got %+v", diagnostics)
+	}
+}
+
+func TestServer_InvalidHeader(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	diagnostics := c.open("file:///COMMIT_EDITMSG", "just some text")
+	if len(diagnostics) != 1 || diagnostics[0].Code != "header" {
+		t.Errorf("expected a header diagnostic, got %+v", diagnostics)
+	}
+
+	if diagnostics := c.open("file:///EMPTY", "\n# comment\n"); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics for an empty message, got %+v", diagnostics)
+	}
+}
+
+func TestServer_CodeAction(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	uri := "file:///COMMIT_EDITMSG"
+	c.open(uri, "fix(Ünïcode): Handle it.")
+
+	var actions []codeAction
+	c.request("textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"range":        Range{Start: Position{0, 0}, End: Position{0, 30}},
+		"context":      map[string]any{"diagnostics": []any{}},
+	}, &actions)
+
+	if len(actions) != 2 {
+		t.Fatalf("expected 2 code actions, got %d: %+v", len(actions), actions)
+	}
+
+	scope := actions[0].Edit.Changes[uri][0]
+	if scope.NewText != "ünïcode" || scope.Range.Start.Character != 4 ||
+		scope.Range.End.Character != 11 {
+		t.Errorf("unexpected scope edit: %+v", scope)
+	}
+
+	subject := actions[1].Edit.Changes[uri][0]
+	if subject.NewText != "handle it" || subject.Range.Start.Character != 14 {
+		t.Errorf("unexpected subject edit: %+v", subject)
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	tests := []struct {
+		name     string
+		text     string
+		position Position
+		want     string
+	}{
+		{"types", "fe", Position{0, 2}, "feat"},
+		{"scopes", "feat(", Position{0, 5}, "parser"},
+		{"footers", "feat: add it\n\nRe", Position{2, 2}, "Refs"},
+		{"trailers", "feat: add it\n\nSig", Position{2, 3}, "Signed-off-by"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := "file:///" + tt.name
+			c.open(uri, tt.text)
+
+			var items []completionItem
+			c.request("textDocument/completion", map[string]any{
+				"textDocument": map[string]any{"uri": uri},
+				"position":     tt.position,
+			}, &items)
+
+			labels := []string{}
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+			if !strings.Contains(strings.Join(labels, ","), tt.want) {
+				t.Errorf("expected %q among completions, got %v", tt.want, labels)
+			}
+		})
+	}
+}
+
+func TestServer_Hover(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	uri := "file:///COMMIT_EDITMSG"
+	c.open(uri, "feat(Lsp): add hover")
+
+	var result hover
+	c.request("textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     Position{0, 6},
+	}, &result)
+
+	if !strings.Contains(result.Contents.Value, "scope-case") {
+		t.Errorf("expected scope-case documentation, got %q", result.Contents.Value)
+	}
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	var result any
+	c.request("shutdown", nil, &result)
+	c.send(map[string]any{"method": "exit"})
+
+	if err := <-c.done; err != nil {
+		t.Errorf("expected clean exit, got %v", err)
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	c.send(map[string]any{"method": "exit"})
+
+	if err := <-c.done; err == nil {
+		t.Error("expected error when exiting without shutdown, got nil")
+	}
+}
+
+func TestServer_MethodNotFound(t *testing.T) {
+	c := newTestClient(t)
+	c.initialize()
+
+	c.send(map[string]any{"id": 99, "method": "workspace/unknown"})
+	if _, ok := c.receive()["error"]; !ok {
+		t.Error("expected an error response for an unknown method")
+	}
+}
+
+func TestUTF16Offsets(t *testing.T) {
+	s := "a😀b"
+
+	if got := utf16Offset(s, 5); got != 3 {
+		t.Errorf("utf16Offset() = %d, want 3", got)
+	}
+	if got := byteOffset(s, 3); got != 5 {
+		t.Errorf("byteOffset() = %d, want 5", got)
+	}
+}
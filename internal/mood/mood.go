@@ -0,0 +1,119 @@
+// Package mood detects English verbs which are not in the imperative mood, like
+// "added", "adds" or "adding", using a lexicon of verbs embedded in the binary so
+// that it works offline.
+package mood
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Form is the grammatical form of a verb which is not in the imperative mood.
+type Form string
+
+// The forms of the verbs which are detected.
+const (
+	PastTense   Form = "past tense"
+	ThirdPerson Form = "third person"
+	Gerund      Form = "gerund"
+)
+
+//go:embed verbs.txt
+var verbsFile string
+
+//go:embed adjectives.txt
+var adjectivesFile string
+
+var (
+	// verbs is the set of the base forms of the verbs of the lexicon
+	verbs = map[string]bool{}
+
+	// irregular maps the irregular past forms of the verbs to their base form
+	irregular = map[string]string{}
+
+	// adjectives is the set of the inflected forms which are not reported
+	adjectives = map[string]bool{}
+)
+
+func init() {
+	for _, fields := range entries(verbsFile) {
+		verbs[fields[0]] = true
+		for _, form := range fields[1:] {
+			irregular[form] = fields[0]
+		}
+	}
+
+	for _, fields := range entries(adjectivesFile) {
+		adjectives[fields[0]] = true
+	}
+}
+
+// entries returns the fields of each line of the lexicon file, skipping the blank and
+// the comment lines.
+func entries(file string) [][]string {
+	result := [][]string{}
+	for _, line := range strings.Split(file, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 &&
+			!strings.HasPrefix(fields[0], "#") {
+			result = append(result, fields)
+		}
+	}
+	return result
+}
+
+// suffixes lists the inflection suffixes along with the form they denote and the
+// ways of deriving the base form from the stem left after removing them, tried in
+// order (e.g. "applies" → "appl" → "apply", "created" → "creat" → "create").
+var suffixes = []struct {
+	suffix  string
+	form    Form
+	endings []string
+}{
+	{"ies", ThirdPerson, []string{"y"}},
+	{"ied", PastTense, []string{"y"}},
+	{"es", ThirdPerson, []string{"e", ""}},
+	{"s", ThirdPerson, []string{""}},
+	{"ed", PastTense, []string{"e", "", undouble}},
+	{"ing", Gerund, []string{"", "e", undouble}},
+}
+
+// undouble is the pseudo-ending denoting the removal of the consonant doubled before
+// the suffix, e.g. "stopped" → "stopp" → "stop".
+const undouble = "-"
+
+// Imperative returns the imperative form of the word along with its current form if
+// the word is a verb of the lexicon in the past tense, the third person or the gerund.
+// Returns false if the word is in the imperative mood or is not a known verb.
+func Imperative(word string) (string, Form, bool) {
+	word = strings.ToLower(word)
+	if verbs[word] || adjectives[word] {
+		return "", "", false
+	}
+
+	if base, ok := irregular[word]; ok {
+		return base, PastTense, true
+	}
+
+	for _, s := range suffixes {
+		stem, ok := strings.CutSuffix(word, s.suffix)
+		if !ok || stem == "" {
+			continue
+		}
+
+		for _, ending := range s.endings {
+			base := stem + ending
+			if ending == undouble {
+				n := len(stem)
+				if n < 2 || stem[n-1] != stem[n-2] {
+					continue
+				}
+				base = stem[:n-1]
+			}
+			if verbs[base] {
+				return base, s.form, true
+			}
+		}
+	}
+
+	return "", "", false
+}
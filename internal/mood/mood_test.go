@@ -0,0 +1,68 @@
+package mood
+
+import "testing"
+
+func TestImperative(t *testing.T) {
+	tests := []struct {
+		word string
+		base string
+		form Form
+	}{
+		{"added", "add", PastTense},
+		{"created", "create", PastTense},
+		{"stopped", "stop", PastTense},
+		{"applied", "apply", PastTense},
+		{"wrote", "write", PastTense},
+		{"rewritten", "rewrite", PastTense},
+		{"Fixed", "fix", PastTense},
+		{"adds", "add", ThirdPerson},
+		{"fixes", "fix", ThirdPerson},
+		{"updates", "update", ThirdPerson},
+		{"copies", "copy", ThirdPerson},
+		{"does", "do", ThirdPerson},
+		{"adding", "add", Gerund},
+		{"creating", "create", Gerund},
+		{"running", "run", Gerund},
+		{"auto-detects", "auto-detect", ThirdPerson},
+	}
+
+	for _, tt := range tests {
+		base, form, ok := Imperative(tt.word)
+		if !ok || base != tt.base || form != tt.form {
+			t.Errorf(
+				"Imperative(%q) = (%q, %q, %v), want (%q, %q, true)",
+				tt.word,
+				base,
+				form,
+				ok,
+				tt.base,
+				tt.form,
+			)
+		}
+	}
+}
+
+func TestImperative_Accepted(t *testing.T) {
+	words := []string{
+		"add",
+		"bring",
+		"need",
+		"speed",
+		"process",
+		"address",
+		"this",
+		"status",
+		"settings",
+		"broken",
+		"missing",
+		"embedded",
+		"bound",
+		"",
+	}
+
+	for _, word := range words {
+		if base, form, ok := Imperative(word); ok {
+			t.Errorf("Imperative(%q) = (%q, %q), want no violation", word, base, form)
+		}
+	}
+}
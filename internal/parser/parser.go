@@ -3,18 +3,95 @@
 package parser
 
 import (
+	"cmp"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // CommitMessage represents a structured Git commit message.
 // The struct fields corresponds to components in the Conventional Commits specification
 type CommitMessage struct {
-	Type, Scope, Description, Body string
-	Footers                        map[string]string
+	Type        string            `json:"type"`
+	Scope       string            `json:"scope"`
+	Description string            `json:"description"`
+	Body        string            `json:"body"`
+	Footers     map[string]string `json:"footers"`
+
+	// Trailers lists the footers and the other trailers (e.g. "Signed-off-by") in the
+	// order they appear, including the repeated keys the Footers map can not hold. It
+	// is only populated by ParseCommitMessage and takes precedence over the Footers on
+	// serialisation.
+	Trailers []Trailer `json:"trailers,omitempty"`
+
+	// References lists the issues referenced in the footers and the body. It is only
+	// populated by ParseCommitMessage.
+	References []Reference `json:"references,omitempty"`
+
+	// Spans and Lines record where the components were found in the original message.
+	// They are only populated by ParseCommitMessage and ignored on serialisation.
+	Spans HeaderSpans `json:"spans"`
+	Lines []Line      `json:"lines,omitempty"`
+}
+
+// Span is a half-open byte range [Start, End) within the header line.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// HeaderSpans holds the location of each component of the commit message header. The
+// Scope span is nil if the header has no scope.
+type HeaderSpans struct {
+	Type        Span  `json:"type"`
+	Scope       *Span `json:"scope,omitempty"`
+	Description Span  `json:"description"`
 }
 
+// Trailer is a single footer or Git trailer of the commit message. The key of the
+// recognised footers is spelled canonically, the other trailers are kept verbatim. The
+// value includes the continuation lines of a multi-line value.
+type Trailer struct {
+	Key       string `json:"key"`
+	Separator string `json:"separator"`
+	Value     string `json:"value"`
+}
+
+// String renders the trailer as "<KEY><SEPARATOR><VALUE>".
+func (t Trailer) String() string {
+	return t.Key + t.Separator + t.Value
+}
+
+// LineKind classifies a line of the commit message by the component it belongs to.
+type LineKind string
+
+// The kinds of lines a commit message is made up of. A line is "ignored" when it is
+// neither part of the body nor a recognised footer, e.g. an unknown trailer following
+// the footers.
+const (
+	LineHeader  LineKind = "header"
+	LineBlank   LineKind = "blank"
+	LineBody    LineKind = "body"
+	LineFooter  LineKind = "footer"
+	LineIgnored LineKind = "ignored"
+)
+
+// Line is a single line of the commit message along with its (1-based) line number and
+// its classification. The text of the line is kept verbatim, including any trailing
+// whitespace.
+type Line struct {
+	Number int      `json:"number"`
+	Kind   LineKind `json:"kind"`
+	Text   string   `json:"text"`
+}
+
+// headerPattern is the regex pattern used to parse the commit message header
+var headerPattern = regexp.MustCompile(
+	`^(?P<Type>\w+)(?:\((?P<Scope>[^\)]+)\))?: (?P<Description>.+)$`,
+)
+
 // parseHeader extracts the type, scope and description from the commit message header.
 // It returns an error if the header does not conform to the Conventional Commits
 // format.
@@ -24,10 +101,7 @@ type CommitMessage struct {
 //	Input: "feat(parser): add support for new syntax"
 //	Output: "feat", "parser", "add support for new syntax", nil
 func parseHeader(header string) (string, string, string, error) {
-	// Create the regex pattern to parse the header
-	re := regexp.MustCompile(
-		`^(?P<Type>\w+)(?:\((?P<Scope>[^\)]+)\))?: (?P<Description>.+)$`,
-	)
+	re := headerPattern
 
 	// Parse the header into it sections (or throw an error on parsing failure)
 	match := re.FindStringSubmatch(header)
@@ -61,77 +135,236 @@ func parseHeader(header string) (string, string, string, error) {
 
 }
 
+// footerOrder lists the recognised footer keys in the order they are emitted when a
+// CommitMessage is serialised back into its textual form.
+var footerOrder = []string{"BREAKING CHANGE", "Closes", "Fixes", "Refs"}
+
+// parseHeaderSpans locates the type, scope and description within a header which is
+// known to be valid.
+func parseHeaderSpans(header string) HeaderSpans {
+	idx := headerPattern.FindStringSubmatchIndex(header)
+	if idx == nil {
+		return HeaderSpans{}
+	}
+
+	spans := HeaderSpans{
+		Type:        Span{Start: idx[2], End: idx[3]},
+		Description: Span{Start: idx[6], End: idx[7]},
+	}
+	if idx[4] >= 0 {
+		spans.Scope = &Span{Start: idx[4], End: idx[5]}
+	}
+
+	return spans
+}
+
 // isKnownFooter checks whether a given key is a recognised Conventional Commits footer.
 // Returns true if the key matches one of the known footers.
 func isKnownFooter(key string) bool {
-	switch key {
-	case "BREAKING CHANGE", "Closes", "Fixes", "Refs":
-		return true
-	default:
-		return false
+	return slices.Contains(footerOrder, key)
+}
+
+// canonicalFooter returns the canonical spelling of a footer key. Keys are matched
+// case-insensitively, except "BREAKING CHANGE" which must be uppercase as required by
+// the Conventional Commits specification, and "BREAKING-CHANGE" is accepted as a
+// synonym of it. Returns false if the key is not a recognised footer.
+func canonicalFooter(key string) (string, bool) {
+	if key == "BREAKING CHANGE" || key == "BREAKING-CHANGE" {
+		return "BREAKING CHANGE", true
 	}
+
+	for _, known := range footerOrder {
+		if known != "BREAKING CHANGE" && strings.EqualFold(key, known) {
+			return known, true
+		}
+	}
+
+	return "", false
+}
+
+// KnownFooters returns the footer keys recognised by the parser in the order they are
+// serialised.
+func KnownFooters() []string {
+	return slices.Clone(footerOrder)
+}
+
+// IsKnownFooter reports whether key is a footer recognised by the parser. Footers with
+// unknown keys are treated as part of the commit message body.
+func IsKnownFooter(key string) bool {
+	return isKnownFooter(key)
 }
 
 // tryParseFooter attempts to parse a single line into a known footer key-value pair.
 // Returns the key, value and true if the line is a valid footer, otherwise returns
 // false.
 func tryParseFooter(line string) (string, string, bool) {
-	// Split the commit message footer into two parts at the ":" (colon) seperator
-	parts := strings.SplitN(line, ":", 2)
-	if len(parts) != 2 {
-		return "", "", false
+	key, _, val, ok := parseFooter(line)
+	return key, val, ok
+}
+
+// parseFooter splits a line into the key, separator and value of a known footer. The
+// key is separated from the value by a ":" (colon), or by a " #" when the value is an
+// issue reference, e.g. "Closes #12", in which case the "#" is kept in the value.
+func parseFooter(line string) (string, string, string, bool) {
+	// Split the commit message footer into two parts at the ":" (colon) seperator and
+	// check if the key is recognised according to the Conventional Commits
+	// specification, normalising its spelling
+	if key, val, found := strings.Cut(line, ":"); found {
+		if key, ok := canonicalFooter(strings.TrimSpace(key)); ok {
+			return key, ": ", strings.TrimSpace(val), true
+		}
+	}
+
+	if key, val, found := strings.Cut(line, " #"); found {
+		if key, ok := canonicalFooter(strings.TrimSpace(key)); ok {
+			return key, " ", "#" + strings.TrimSpace(val), true
+		}
+	}
+
+	return "", "", "", false
+}
+
+// trailerLinePattern matches a line formatted like a Git trailer, e.g.
+// "Signed-off-by: Jane Doe <jane@example.com>" or "Refs #12", capturing its key along
+// with either its separator and value or the issue reference following a space.
+var trailerLinePattern = regexp.MustCompile(
+	`^(BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:(:[ \t]*)(\S.*)| (#\S.*))$`,
+)
+
+// parseTrailer splits a line formatted like a trailer into its key, separator and
+// value. Returns false if the line is not a trailer.
+func parseTrailer(line string) (Trailer, bool) {
+	m := trailerLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return Trailer{}, false
+	}
+	if m[2] == "" {
+		return Trailer{Key: m[1], Separator: " ", Value: m[4]}, true
+	}
+	return Trailer{Key: m[1], Separator: m[2], Value: m[3]}, true
+}
+
+// trailerBlockStart returns the index of the first line of the footers following the
+// body, or len(lines) if there are none. The footers are found in the last paragraph
+// only, which holds them if it is only made of trailers (and of the indented lines
+// continuing their values). Otherwise, the trailers ending the paragraph are only the
+// footers if they include a recognised footer, e.g. "Closes: #12" below a line of the
+// body.
+func trailerBlockStart(lines []string) int {
+	blank := func(idx int) bool {
+		return strings.TrimSpace(lines[idx]) == ""
+	}
+	trailer := func(idx int) bool {
+		_, ok := parseTrailer(strings.TrimRightFunc(lines[idx], unicode.IsSpace))
+		return ok
+	}
+	continuation := func(idx int) bool {
+		return strings.HasPrefix(lines[idx], " ") || strings.HasPrefix(lines[idx], "\t")
 	}
 
-	key := strings.TrimSpace(parts[0])
-	val := strings.TrimSpace(parts[1])
+	end := len(lines)
+	for end > 0 && blank(end-1) {
+		end--
+	}
+	first := end
+	for first > 0 && !blank(first-1) {
+		first--
+	}
 
-	// Check if the key-value pair of the footer are recognised according to the
-	// Conventional Commits specification
-	if isKnownFooter(key) {
-		return key, val, true
+	// Find the trailers ending the last paragraph, the continuation lines are only
+	// part of them below a trailer
+	start := end
+	for idx := end - 1; idx >= first; idx-- {
+		if trailer(idx) {
+			start = idx
+		} else if !continuation(idx) {
+			break
+		}
 	}
 
-	return "", "", false
+	if start == end {
+		return len(lines)
+	}
+	if start == first && first > 0 {
+		return start
+	}
+	for idx := start; idx < end; idx++ {
+		if _, _, ok := tryParseFooter(lines[idx]); ok && !continuation(idx) {
+			return start
+		}
+	}
+	return len(lines)
 }
 
 // parseBodyAndFooter splits the commit message lines into body and footers.
 // Returns a cleaned body text and a map of parsed footers.
 func parseBodyAndFooter(lines []string) (string, map[string]string) {
+	body, footers, _, _ := parseBodyAndFooterLines(lines, 2)
+	return body, footers
+}
+
+// parseBodyAndFooterLines splits the commit message lines into body and footers like
+// parseBodyAndFooter does. Additionally, it returns the ordered trailers and classifies
+// each of the lines, numbering them starting from firstLine.
+func parseBodyAndFooterLines(
+	lines []string,
+	firstLine int,
+) (string, map[string]string, []Trailer, []Line) {
 	bodyLines := []string{}        // The body content initially set to an empty string
 	footers := map[string]string{} // The footers initially set to an empty map
-	inFooter := false              // Boolean flag to check body/footer parsing state
+	trailers := []Trailer{}        // The footers and trailers in their original order
+	classified := []Line{}         // The classification of each of the lines
+	start := trailerBlockStart(lines)
 
-	// Loop through the lines, trimming all whitespaces and try to parse the body/footer
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	// Loop through the lines, trimming trailing whitespaces and try to parse the
+	// body/footer. The leading indentation is kept intact to preserve code blocks.
+	for idx, raw := range lines {
+		line := strings.TrimRightFunc(raw, unicode.IsSpace)
+		classified = append(classified, Line{Number: firstLine + idx, Text: raw})
+		current := &classified[len(classified)-1]
 
 		// On an empty line, if it is not a footer, continue looping through the content
 		// and parsing it
-		if line == "" {
-			if !inFooter {
+		if strings.TrimSpace(line) == "" {
+			current.Kind = LineBlank
+			if idx < start {
 				bodyLines = append(bodyLines, "")
 			}
 			continue
 		}
 
-		// Parse the footer content and construct the "footers" map
-		if key, val, ok := tryParseFooter(line); ok {
-			footers[key] = val
-			inFooter = true
-			continue
-		}
-
 		// If the parsing logic is outside the footer section then append the body
 		// strings to the list of the body content
-		if !inFooter {
+		if idx < start {
+			current.Kind = LineBody
 			bodyLines = append(bodyLines, line)
+			continue
+		}
+
+		// The lines of the footers are either trailers or the indented continuation of
+		// the value of the trailer right above them
+		current.Kind = LineIgnored
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			trailers[len(trailers)-1].Value += "\n" + line
+			continue
 		}
+
+		// Parse the footer content and construct the "footers" map, keeping the other
+		// trailers verbatim
+		if key, sep, val, ok := parseFooter(line); ok {
+			current.Kind = LineFooter
+			footers[key] = val
+			trailers = append(trailers, Trailer{Key: key, Separator: sep, Value: val})
+			continue
+		}
+		trailer, _ := parseTrailer(line)
+		trailers = append(trailers, trailer)
 	}
 
 	// Construct the commit message body from the list of the body content parsed above
-	body := strings.TrimSpace(strings.Join(bodyLines, "\n"))
+	body := strings.Trim(strings.Join(bodyLines, "\n"), "\n")
 
-	return body, footers
+	return body, footers, trailers, classified
 }
 
 // ParseCommitMessage parses a commit message string into its components, including
@@ -163,7 +396,8 @@ func ParseCommitMessage(message string) (*CommitMessage, error) {
 	}
 
 	// Parse the body and footer contents of the commit message
-	body, footers := parseBodyAndFooter(lines[1:])
+	body, footers, trailers, classified := parseBodyAndFooterLines(lines[1:], 2)
+	header := Line{Number: 1, Kind: LineHeader, Text: lines[0]}
 
 	// Return an instantiated struct for further processing and validation if no errors
 	// were raised earlier
@@ -173,5 +407,109 @@ func ParseCommitMessage(message string) (*CommitMessage, error) {
 		Description: desc,
 		Body:        body,
 		Footers:     footers,
+		Trailers:    trailers,
+		References:  ParseReferences(classified),
+		Spans:       parseHeaderSpans(lines[0]),
+		Lines:       append([]Line{header}, classified...),
 	}, nil
 }
+
+// Header returns the canonical header line of the commit message, i.e.
+// "<TYPE>(<SCOPE>): <DESCRIPTION>" with the scope omitted when empty.
+func (c *CommitMessage) Header() string {
+	if c.Scope != "" {
+		return fmt.Sprintf("%s(%s): %s", c.Type, c.Scope, c.Description)
+	}
+	return fmt.Sprintf("%s: %s", c.Type, c.Description)
+}
+
+// String serialises the commit message into its canonical textual form.
+//
+// The header, body and footers are separated by a single blank line each. The
+// recognised footers are emitted in a fixed order followed by the other trailers in
+// their original order, so parsing the returned string with ParseCommitMessage yields
+// an identical CommitMessage.
+func (c *CommitMessage) String() string {
+	sections := []string{c.Header()}
+
+	if body := strings.Trim(c.Body, "\n"); body != "" {
+		sections = append(sections, body)
+	}
+
+	trailers := slices.Clone(c.Trailers)
+	if trailers == nil {
+		for _, key := range footerOrder {
+			if val, ok := c.Footers[key]; ok {
+				trailer := Trailer{Key: key, Separator: ": ", Value: val}
+				trailers = append(trailers, trailer)
+			}
+		}
+	}
+	slices.SortStableFunc(trailers, func(a, b Trailer) int {
+		return cmp.Compare(footerRank(a.Key), footerRank(b.Key))
+	})
+
+	footers := []string{}
+	for _, trailer := range trailers {
+		footers = append(footers, trailer.String())
+	}
+	if len(footers) > 0 {
+		sections = append(sections, strings.Join(footers, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// footerRank returns the position of a footer in the serialisation order. The other
+// trailers come after the recognised footers.
+func footerRank(key string) int {
+	if idx := slices.Index(footerOrder, key); idx >= 0 {
+		return idx
+	}
+	return len(footerOrder)
+}
+
+// scissors is the line below which Git discards the commit message (e.g. the diff
+// added by "git commit --verbose").
+const scissors = "# ------------------------ >8 ------------------------"
+
+// StripComments blanks out the comment lines (starting with "#") Git adds to the
+// commit message template and everything below the scissors line. The lines are
+// blanked instead of being removed so that the line numbers remain unchanged, except
+// for the empty lines left at the end of the message which Git discards as well.
+func StripComments(message string) string {
+	lines := strings.Split(message, "\n")
+
+	cut := false
+	for idx, line := range lines {
+		cut = cut || line == scissors
+		if cut || strings.HasPrefix(line, "#") {
+			lines[idx] = ""
+		}
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// TrailingComments returns the comment lines at the end of the message along with
+// everything below the scissors line, i.e. the block Git adds to the commit message
+// template. Returns an empty string if there is no such block.
+func TrailingComments(message string) string {
+	lines := strings.Split(message, "\n")
+
+	end := len(lines)
+	if idx := slices.Index(lines, scissors); idx >= 0 {
+		end = idx
+	}
+
+	start := end
+	for idx := end - 1; idx >= 0; idx-- {
+		if strings.HasPrefix(lines[idx], "#") {
+			start = idx
+		} else if strings.TrimSpace(lines[idx]) != "" {
+			break
+		}
+	}
+
+	return strings.Join(lines[start:], "\n")
+}
@@ -72,6 +72,10 @@ func TestTryParseFooter(t *testing.T) {
 			true,
 		},
 		{"Fixes: #123", "Fixes", "#123", true},
+		{"Closes #12", "Closes", "#12", true},
+		{"refs #12, #13", "Refs", "#12, #13", true},
+		{"Closes #12: the parser", "Closes", "#12: the parser", true},
+		{"Issue #12", "", "", false},
 		{"Random line", "", "", false},
 		{"NotAFooter - no colon", "", "", false},
 	}
@@ -175,3 +179,315 @@ func TestIsKnownFooter(t *testing.T) {
 		t.Error("expected BREAKING CHANGE to be known footer")
 	}
 }
+
+func TestCommitMessage_Header(t *testing.T) {
+	tests := []struct {
+		msg  CommitMessage
+		want string
+	}{
+		{
+			CommitMessage{Type: "feat", Scope: "parser", Description: "add serialiser"},
+			"feat(parser): add serialiser",
+		},
+		{CommitMessage{Type: "fix", Description: "correct typo"}, "fix: correct typo"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.msg.Header(); got != tt.want {
+			t.Errorf("Header() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCommitMessage_String(t *testing.T) {
+	msg := &CommitMessage{
+		Type:        "feat",
+		Scope:       "auth",
+		Description: "add OAuth login",
+		Body:        "Implements login with OAuth 2.0.\n\nSecond paragraph.",
+		Footers: map[string]string{
+			"Refs":            "#12",
+			"BREAKING CHANGE": "existing login method removed",
+		},
+	}
+
+	want := `feat(auth): add OAuth login
+
+Implements login with OAuth 2.0.
+
+Second paragraph.
+
+BREAKING CHANGE: existing login method removed
+Refs: #12`
+
+	got := msg.String()
+	if got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+
+	// The serialised message must parse back into an identical structure
+	parsed, err := ParseCommitMessage(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed.References, parsed.Spans, parsed.Lines = nil, HeaderSpans{}, nil
+	parsed.Trailers = nil
+	if !reflect.DeepEqual(parsed, msg) {
+		t.Errorf("round-trip mismatch: got %#v, want %#v", parsed, msg)
+	}
+}
+
+func TestCommitMessage_String_Trailers(t *testing.T) {
+	tests := []string{
+		"fix: handle it\n\nRefs: #1\nRefs: #2",
+		"fix: handle it\n\nBody.\n\nSigned-off-by: Jane Doe <jane@example.com>\n" +
+			"Co-authored-by: John Doe <john@example.com>",
+		"fix: handle it\n\nBody.\n\nBREAKING CHANGE: the old format is gone\n" +
+			"  and can not be read anymore\nRefs: #1\nRefs: #2\nReviewed-by: Jane",
+		"fix: handle it\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		"fix: handle it\n\nFixes #12\nRefs: #13",
+		"fix: handle it\n\nIntro.\n\nRefs: #1\n\nMore explanation after this.",
+		"fix: handle it\n\nfixes: the crash on an empty input\nwhich was reported.",
+	}
+
+	for _, message := range tests {
+		msg, err := ParseCommitMessage(message)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", message, err)
+		}
+		if got := msg.String(); got != message {
+			t.Errorf("String() = %q, want %q", got, message)
+		}
+	}
+}
+
+func TestParseCommitMessage_Trailers(t *testing.T) {
+	message := "fix: handle it\n\nBody.\n\nSigned-off-by: Jane <jane@example.com>\n" +
+		"Refs: #1\nRefs: #2"
+
+	got, err := ParseCommitMessage(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Trailer{
+		{Key: "Signed-off-by", Separator: ": ", Value: "Jane <jane@example.com>"},
+		{Key: "Refs", Separator: ": ", Value: "#1"},
+		{Key: "Refs", Separator: ": ", Value: "#2"},
+	}
+	if !reflect.DeepEqual(got.Trailers, want) {
+		t.Errorf("expected trailers %+v, got %+v", want, got.Trailers)
+	}
+	if got.Body != "Body." {
+		t.Errorf("expected body %q, got %q", "Body.", got.Body)
+	}
+
+	// Lines formatted like trailers in the middle of the body are part of it
+	got, err = ParseCommitMessage("fix: handle it\n\nNote: it works.\n\nMore text.")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Trailers) != 0 {
+		t.Errorf("expected no trailers, got %+v", got.Trailers)
+	}
+}
+
+func TestParseCommitMessage_IssueFooters(t *testing.T) {
+	got, err := ParseCommitMessage("fix: handle it\n\nBody.\n\nCloses #12\nRefs #13")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	footers := map[string]string{"Closes": "#12", "Refs": "#13"}
+	if !reflect.DeepEqual(got.Footers, footers) {
+		t.Errorf("expected footers %v, got %v", footers, got.Footers)
+	}
+	trailers := []Trailer{
+		{Key: "Closes", Separator: " ", Value: "#12"},
+		{Key: "Refs", Separator: " ", Value: "#13"},
+	}
+	if !reflect.DeepEqual(got.Trailers, trailers) {
+		t.Errorf("expected trailers %+v, got %+v", trailers, got.Trailers)
+	}
+	if got.Body != "Body." {
+		t.Errorf("expected body %q, got %q", "Body.", got.Body)
+	}
+}
+
+func TestParseCommitMessage_TrailersParagraph(t *testing.T) {
+	tests := []struct {
+		message string
+		body    string
+	}{
+		{
+			"fix: handle it\n\nIntro.\n\nRefs: #1\n\nMore explanation after this.",
+			"Intro.\n\nRefs: #1\n\nMore explanation after this.",
+		},
+		{
+			"fix: handle it\n\nfixes: the crash on an empty input\nwhich was reported.",
+			"fixes: the crash on an empty input\nwhich was reported.",
+		},
+		{
+			"fix: handle it\n\nbreaking change: the old format is gone",
+			"breaking change: the old format is gone",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCommitMessage(tt.message)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.message, err)
+		}
+		if got.Body != tt.body || len(got.Footers) != 0 || len(got.Trailers) != 0 {
+			t.Errorf(
+				"%q: expected the body %q without footers, got %q and %+v",
+				tt.message,
+				tt.body,
+				got.Body,
+				got.Trailers,
+			)
+		}
+	}
+}
+
+func TestCommitMessage_String_HeaderOnly(t *testing.T) {
+	msg := &CommitMessage{Type: "docs", Description: "fix typo", Footers: nil}
+
+	if got := msg.String(); got != "docs: fix typo" {
+		t.Errorf("String() = %q, want %q", got, "docs: fix typo")
+	}
+}
+
+func TestTryParseFooter_Normalised(t *testing.T) {
+	tests := []struct {
+		input     string
+		expectKey string
+	}{
+		{"closes: #1", "Closes"},
+		{"REFS: #2", "Refs"},
+		{"BREAKING-CHANGE: gone", "BREAKING CHANGE"},
+	}
+
+	for _, tt := range tests {
+		key, _, ok := tryParseFooter(tt.input)
+		if !ok || key != tt.expectKey {
+			t.Errorf("for input %q: expected key %q, got %q (ok=%v)", tt.input,
+				tt.expectKey, key, ok)
+		}
+	}
+
+	// The BREAKING CHANGE token must be uppercase
+	for _, input := range []string{"breaking change: gone", "Breaking-Change: gone"} {
+		if key, _, ok := tryParseFooter(input); ok {
+			t.Errorf("for input %q: expected no footer, got %q", input, key)
+		}
+	}
+}
+
+func TestParseBodyAndFooter_PreservesIndentation(t *testing.T) {
+	lines := []string{
+		"",
+		"Run the following:  ",
+		"",
+		"    go test ./...",
+		"",
+	}
+
+	body, _ := parseBodyAndFooter(lines)
+
+	want := "Run the following:\n\n    go test ./..."
+	if body != want {
+		t.Errorf("expected body %q, got %q", want, body)
+	}
+}
+
+func TestParseHeaderSpans(t *testing.T) {
+	header := "feat(parser): add spans"
+	spans := parseHeaderSpans(header)
+
+	if got := header[spans.Type.Start:spans.Type.End]; got != "feat" {
+		t.Errorf("type span = %q, want %q", got, "feat")
+	}
+	if spans.Scope == nil {
+		t.Fatal("expected scope span, got nil")
+	}
+	if got := header[spans.Scope.Start:spans.Scope.End]; got != "parser" {
+		t.Errorf("scope span = %q, want %q", got, "parser")
+	}
+	desc := spans.Description
+	if got := header[desc.Start:desc.End]; got != "add spans" {
+		t.Errorf("description span = %q, want %q", got, "add spans")
+	}
+
+	if spans := parseHeaderSpans("fix: no scope"); spans.Scope != nil {
+		t.Errorf("expected nil scope span, got %+v", spans.Scope)
+	}
+}
+
+func TestParseCommitMessage_Lines(t *testing.T) {
+	message := "fix: drop footers\n\nBody text.\n\nRefs: #1\nSigned-off-by: A <a@b.c>"
+
+	got, err := ParseCommitMessage(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []LineKind{
+		LineHeader,
+		LineBlank,
+		LineBody,
+		LineBlank,
+		LineFooter,
+		LineIgnored,
+	}
+	if len(got.Lines) != len(want) {
+		t.Fatalf("expected %d lines, got %d", len(want), len(got.Lines))
+	}
+	for idx, line := range got.Lines {
+		if line.Number != idx+1 || line.Kind != want[idx] {
+			t.Errorf(
+				"line %d: expected (%d, %s), got (%d, %s)",
+				idx,
+				idx+1,
+				want[idx],
+				line.Number,
+				line.Kind,
+			)
+		}
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	message := "feat: add it\n\n# Please enter the commit message\nBody\n" +
+		"# ------------------------ >8 ------------------------\ndiff --git a/x b/x"
+
+	want := "feat: add it\n\n\nBody"
+	if got := StripComments(message); got != want {
+		t.Errorf("StripComments() = %q, want %q", got, want)
+	}
+}
+
+func TestTrailingComments(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat: add it\n", ""},
+		{"feat: add it\n\n# Please enter\n#\n", "# Please enter\n#\n"},
+		{"feat: add it\n\n# Note\nBody\n", ""},
+		{
+			"feat: add it\n\n# Please enter\n" + scissors + "\ndiff --git a/x b/x\n",
+			"# Please enter\n" + scissors + "\ndiff --git a/x b/x\n",
+		},
+		{
+			"feat: add it\n" + scissors + "\n# Do not modify\n",
+			scissors + "\n# Do not modify\n",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := TrailingComments(tt.message); got != tt.want {
+			t.Errorf("TrailingComments(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReferenceKind is the format of an issue reference.
+type ReferenceKind string
+
+// The formats of the issue references. Issues are referenced either by their number
+// (e.g. "#123", "owner/repo#123" or "GH-123") or by a Jira-style key made up of the
+// key of a project and a number (e.g. "PROJ-123").
+const (
+	ReferenceIssue ReferenceKind = "issue"
+	ReferenceKey   ReferenceKind = "key"
+)
+
+// Reference is an issue referenced by the commit message.
+type Reference struct {
+	Kind ReferenceKind `json:"kind"`
+
+	// Text is the reference as it is written, e.g. "owner/repo#123".
+	Text string `json:"text"`
+
+	// Repository is the "owner/repo" the issue belongs to, if given.
+	Repository string `json:"repository,omitempty"`
+
+	// Project is the key of the project of a Jira-style key, e.g. "PROJ".
+	Project string `json:"project,omitempty"`
+
+	// Number is the number of the issue.
+	Number int `json:"number"`
+
+	// Footer is the key of the footer the reference was found in, e.g. "Closes". It
+	// is empty for the references found in the body.
+	Footer string `json:"footer,omitempty"`
+
+	// Line is the (1-based) number of the line the reference was found in.
+	Line int `json:"line"`
+}
+
+// referenceFooters lists the footers whose values are parsed for issue references.
+var referenceFooters = []string{"Closes", "Fixes", "Refs"}
+
+// referencePattern matches the issue references, which are checked for word
+// boundaries separately.
+var referencePattern = regexp.MustCompile(
+	`(?:(?P<repo>[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)?#|GH-)(?P<number>[0-9]+)|` +
+		`(?P<project>[A-Z][A-Z0-9]+)-(?P<key>[1-9][0-9]*)`,
+)
+
+// parseReferences returns the issue references found in the given text of a line.
+func parseReferences(text string, line int, footer string) []Reference {
+	references := []Reference{}
+	group := func(m []int, name string) string {
+		idx := referencePattern.SubexpIndex(name)
+		if m[2*idx] < 0 {
+			return ""
+		}
+		return text[m[2*idx]:m[2*idx+1]]
+	}
+
+	for _, m := range referencePattern.FindAllStringSubmatchIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+		after, _ := utf8.DecodeRuneInString(text[m[1]:])
+		if (m[0] > 0 && isReferenceChar(before)) ||
+			(m[1] < len(text) && isReferenceChar(after)) {
+			continue
+		}
+
+		ref := Reference{
+			Kind:       ReferenceIssue,
+			Text:       text[m[0]:m[1]],
+			Repository: group(m, "repo"),
+			Footer:     footer,
+			Line:       line,
+		}
+		number := group(m, "number")
+		if project := group(m, "project"); project != "" {
+			ref.Kind, ref.Project, number = ReferenceKey, project, group(m, "key")
+		}
+		ref.Number, _ = strconv.Atoi(number)
+
+		references = append(references, ref)
+	}
+
+	return references
+}
+
+// isReferenceChar reports whether the rune may be part of a reference, in which case
+// a reference can not start right after it or end right before it.
+func isReferenceChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '/' ||
+		r == '#' || r == '-'
+}
+
+// ParseReferences returns the issue references of the classified lines of a commit
+// message, i.e. the ones found in the "Closes", "Fixes" and "Refs" footers and in the
+// lines of the body, in the order they appear.
+func ParseReferences(lines []Line) []Reference {
+	references := []Reference{}
+
+	for _, line := range lines {
+		switch line.Kind {
+		case LineFooter:
+			key, val, ok := tryParseFooter(line.Text)
+			if ok && slices.Contains(referenceFooters, key) {
+				found := parseReferences(val, line.Number, key)
+				references = append(references, found...)
+			}
+		case LineBody:
+			found := parseReferences(line.Text, line.Number, "")
+			references = append(references, found...)
+		}
+	}
+
+	return references
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	message := "fix: handle it\n\n" +
+		"Reported in octo/crisp#7 and PROJ-42, not in UTF8-1 or abc#1.\n\n" +
+		"Closes: #12, GH-13\n" +
+		"Refs: OPS-9\n" +
+		"BREAKING CHANGE: #99 is not a reference"
+
+	msg, err := ParseCommitMessage(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Reference{
+		{
+			Kind:       ReferenceIssue,
+			Text:       "octo/crisp#7",
+			Repository: "octo/crisp",
+			Number:     7,
+			Line:       3,
+		},
+		{Kind: ReferenceKey, Text: "PROJ-42", Project: "PROJ", Number: 42, Line: 3},
+		{Kind: ReferenceKey, Text: "UTF8-1", Project: "UTF8", Number: 1, Line: 3},
+		{Kind: ReferenceIssue, Text: "#12", Number: 12, Footer: "Closes", Line: 5},
+		{Kind: ReferenceIssue, Text: "GH-13", Number: 13, Footer: "Closes", Line: 5},
+		{
+			Kind:    ReferenceKey,
+			Text:    "OPS-9",
+			Project: "OPS",
+			Number:  9,
+			Footer:  "Refs",
+			Line:    6,
+		},
+	}
+	if !reflect.DeepEqual(msg.References, want) {
+		t.Errorf("References = %+v, want %+v", msg.References, want)
+	}
+}
+
+func TestParseReferences_IssueFooter(t *testing.T) {
+	msg, err := ParseCommitMessage("fix: handle it\n\nFixes #12")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Reference{
+		{Kind: ReferenceIssue, Text: "#12", Number: 12, Footer: "Fixes", Line: 3},
+	}
+	if !reflect.DeepEqual(msg.References, want) {
+		t.Errorf("References = %+v, want %+v", msg.References, want)
+	}
+}
+
+func TestParseReferences_None(t *testing.T) {
+	messages := []string{
+		"fix: handle it",
+		"fix: handle it (#12)",
+		"fix: handle it\n\nSee https://example.com/page#12 and issue-12.",
+		"fix: handle it\n\nThe C#12 compiler, the 1-2 and the ab-0 are no issues.",
+	}
+
+	for _, message := range messages {
+		msg, err := ParseCommitMessage(message)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(msg.References) != 0 {
+			t.Errorf("%q: expected no references, got %+v", message, msg.References)
+		}
+	}
+}
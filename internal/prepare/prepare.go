@@ -0,0 +1,67 @@
+// Package prepare pre-fills the commit messages being written with the information
+// encoded in the name of the branch, for use in the "prepare-commit-msg" Git hook.
+package prepare
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// ShouldPrefill reports whether a commit message from the given source (as passed to
+// the "prepare-commit-msg" hook by Git) should be pre-filled. Only new commit messages
+// are pre-filled, the messages given with "-m", merges, squashes and amended commits
+// are left untouched.
+func ShouldPrefill(source string) bool {
+	return source == "" || source == "template"
+}
+
+// Prefill returns the message with the header and a "Refs" trailer pre-filled from
+// the branch information. The first word of the topic of the branch is used as the
+// scope only if it is one of the known scopes. The message is returned unchanged if
+// it already has a header, and the comments of Git are kept intact.
+func Prefill(message string, info *validator.BranchInfo, scopes []string) string {
+	if info == nil {
+		return message
+	}
+
+	first, rest, _ := strings.Cut(message, "\n")
+	if strings.TrimSpace(first) != "" && !strings.HasPrefix(first, "#") {
+		return message
+	}
+	if strings.HasPrefix(first, "#") {
+		rest = message
+	}
+
+	header := ""
+	if info.Type != "" {
+		scope := info.Scope
+		if word, _, _ := strings.Cut(info.Topic, "-"); scope == "" &&
+			slices.Contains(scopes, word) {
+			scope = word
+		}
+
+		header = info.Type + ": "
+		if scope != "" {
+			header = info.Type + "(" + scope + "): "
+		}
+	}
+
+	lines := []string{header}
+	if info.Issue != "" &&
+		!strings.Contains(parser.StripComments(message), info.Issue) {
+		lines = append(lines, "", "Refs: "+info.Issue)
+	}
+	if header == "" && len(lines) == 1 {
+		return message
+	}
+
+	lines = append(lines, "")
+	if rest != "" {
+		lines = append(lines, strings.TrimLeft(rest, "\n"))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+package prepare
+
+import (
+	"testing"
+
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// gitMessage is the message Git passes to the hook for a new commit.
+const gitMessage = "\n# Please enter the commit message for your changes.\n#\n" +
+	"# On branch fix/ABC-123-reader-stdin\n"
+
+func TestShouldPrefill(t *testing.T) {
+	tests := map[string]bool{
+		"":         true,
+		"template": true,
+		"message":  false,
+		"merge":    false,
+		"squash":   false,
+		"commit":   false,
+	}
+
+	for source, want := range tests {
+		if got := ShouldPrefill(source); got != want {
+			t.Errorf("ShouldPrefill(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestPrefill(t *testing.T) {
+	scopes := []string{"parser", "reader"}
+
+	tests := []struct {
+		name    string
+		message string
+		info    *validator.BranchInfo
+		want    string
+	}{
+		{
+			name:    "type, known scope and issue",
+			message: gitMessage,
+			info: &validator.BranchInfo{
+				Type:  "fix",
+				Issue: "ABC-123",
+				Topic: "reader-stdin",
+			},
+			want: "fix(reader): \n\nRefs: ABC-123\n\n" +
+				"# Please enter the commit message for your changes.\n#\n" +
+				"# On branch fix/ABC-123-reader-stdin\n",
+		},
+		{
+			name:    "unknown scope",
+			message: gitMessage,
+			info:    &validator.BranchInfo{Type: "feat", Topic: "bang-support"},
+			want:    "feat: \n" + gitMessage,
+		},
+		{
+			name:    "scope from the pattern",
+			message: "",
+			info:    &validator.BranchInfo{Type: "docs", Scope: "cli", Issue: "#12"},
+			want:    "docs(cli): \n\nRefs: #12\n",
+		},
+		{
+			name:    "existing header",
+			message: "feat: already written\n" + gitMessage,
+			info:    &validator.BranchInfo{Type: "fix", Issue: "ABC-123"},
+			want:    "feat: already written\n" + gitMessage,
+		},
+		{
+			name:    "no branch information",
+			message: gitMessage,
+			info:    nil,
+			want:    gitMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Prefill(tt.message, tt.info, scopes); got != tt.want {
+				t.Errorf("Prefill() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
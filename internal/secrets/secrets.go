@@ -0,0 +1,163 @@
+// Package secrets detects credentials pasted into commit messages, like API tokens,
+// private keys or passwords in connection strings, using a rule set embedded in the
+// binary along with an entropy check catching random looking strings.
+package secrets
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+//go:embed rules.json
+var rulesFile []byte
+
+// HighEntropy is the ID of the findings of the entropy check.
+const HighEntropy = "high-entropy"
+
+// minEntropyLength is the minimum length of the strings checked for their entropy,
+// shorter strings cannot be told apart from identifiers.
+const minEntropyLength = 24
+
+// rule is a pattern of the embedded rule set. The secret is the submatch named
+// "secret" if the pattern has one or else the whole match.
+type rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Pattern     string `json:"pattern"`
+
+	regexp *regexp.Regexp
+}
+
+// rules is the embedded rule set, in the order the patterns are matched
+var rules = []rule{}
+
+// tokenPattern matches the candidates of the entropy check, i.e. runs of the
+// characters used by the base64, hex and URL safe encodings.
+var tokenPattern = regexp.MustCompile(`[A-Za-z0-9+/_=-]+`)
+
+func init() {
+	if err := json.Unmarshal(rulesFile, &rules); err != nil {
+		panic("secrets: invalid rule set: " + err.Error())
+	}
+	for i := range rules {
+		rules[i].regexp = regexp.MustCompile(rules[i].Pattern)
+	}
+}
+
+// Finding is a secret found in a text.
+type Finding struct {
+	// Rule is the ID of the rule which found the secret.
+	Rule string
+
+	// Description tells what kind of secret was found, e.g. "GitHub token".
+	Description string
+
+	// Start and End are the byte offsets of the secret in the text.
+	Start, End int
+
+	// Secret is the text of the secret.
+	Secret string
+}
+
+// Fingerprint returns an identifier of the secret which can be shared without
+// disclosing the secret, i.e. the first 16 hex digits of its SHA-256 hash.
+func (f Finding) Fingerprint() string {
+	sum := sha256.Sum256([]byte(f.Secret))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Redacted returns the secret with all but its first few characters masked, e.g.
+// "ghp_****" for a GitHub token. Secrets shorter than 16 characters are fully masked.
+func (f Finding) Redacted() string {
+	runes := []rune(f.Secret)
+	if len(runes) < 16 {
+		return "****"
+	}
+	return string(runes[:4]) + "****"
+}
+
+// Entropy returns the Shannon entropy of the string in bits per character.
+func Entropy(s string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Scan returns the secrets found in the text, ordered by their position. The strings
+// of at least 24 characters mixing letters and digits with an entropy of at least the
+// threshold (in bits per character) are reported as well, unless the threshold is 0.
+// The secrets overlapping a secret found earlier are skipped.
+func Scan(text string, threshold float64) []Finding {
+	findings := []Finding{}
+	add := func(f Finding) {
+		for _, other := range findings {
+			if f.Start < other.End && other.Start < f.End {
+				return
+			}
+		}
+		findings = append(findings, f)
+	}
+
+	for _, r := range rules {
+		group := r.regexp.SubexpIndex("secret")
+		for _, m := range r.regexp.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[0], m[1]
+			if group > 0 && m[2*group] >= 0 {
+				start, end = m[2*group], m[2*group+1]
+			}
+			add(Finding{
+				Rule:        r.ID,
+				Description: r.Description,
+				Start:       start,
+				End:         end,
+				Secret:      text[start:end],
+			})
+		}
+	}
+
+	if threshold > 0 {
+		for _, m := range tokenPattern.FindAllStringIndex(text, -1) {
+			token := text[m[0]:m[1]]
+			if len(token) < minEntropyLength || !isMixed(token) ||
+				Entropy(token) < threshold {
+				continue
+			}
+			add(Finding{
+				Rule:        HighEntropy,
+				Description: "high entropy string",
+				Start:       m[0],
+				End:         m[1],
+				Secret:      token,
+			})
+		}
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return a.Start - b.Start
+	})
+	return findings
+}
+
+// isMixed reports whether the string contains both letters and digits, which rules
+// out most words, paths and identifiers.
+func isMixed(s string) bool {
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0 &&
+		strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
@@ -0,0 +1,202 @@
+// Package server implements an HTTP service which lints the commit messages and pull
+// request titles found in the webhook payloads sent by GitHub, GitLab and Gitea, as
+// well as raw commit messages.
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// maxPayloadSize is the maximum size of a request body accepted by the server.
+const maxPayloadSize = 5 << 20
+
+// Result is the outcome of linting a single commit message or pull request title. The
+// secrets found in the message are redacted, as they are in the error and the
+// diagnostics.
+type Result struct {
+	Kind        string                 `json:"kind"`
+	ID          string                 `json:"id,omitempty"`
+	Message     string                 `json:"message"`
+	Valid       bool                   `json:"valid"`
+	Error       string                 `json:"error,omitempty"`
+	Diagnostics []validator.Diagnostic `json:"diagnostics"`
+}
+
+// Report is the JSON response of the server listing the result of every linted item.
+type Report struct {
+	Provider string   `json:"provider,omitempty"`
+	Valid    bool     `json:"valid"`
+	Results  []Result `json:"results"`
+}
+
+// server holds the configuration of the HTTP service. It is never modified after
+// being constructed, hence it is safe to handle requests concurrently.
+type server struct {
+	secret string
+	config *config.Config
+}
+
+// The NewServer() constructor creates the HTTP handler of the service linting the
+// messages with the given configuration. Webhook payloads are only accepted if they
+// are signed with secret, unless it is empty.
+func NewServer(secret string, cfg *config.Config) http.Handler {
+	s := &server{secret: secret, config: cfg}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("POST /lint", s.handleLint)
+	mux.HandleFunc("POST /webhook", s.handleWebhook)
+
+	return mux
+}
+
+// handleHealth reports that the service is up and running.
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleLint lints the raw commit message(s) sent in the request body. The body is
+// either the plain text of a single message or a JSON object of the form
+// {"messages": ["..."]}.
+func (s *server) handleLint(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	messages := []string{string(body)}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req struct {
+			Messages []string `json:"messages"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		messages = req.Messages
+	}
+
+	items := []item{}
+	for _, message := range messages {
+		items = append(items, item{kind: "message", message: message})
+	}
+
+	writeJSON(w, http.StatusOK, s.lint(items))
+}
+
+// handleWebhook verifies and lints the commit messages and pull request titles of a
+// webhook payload. The provider is detected from the event header of the request.
+func (s *server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	p, ok := detectProvider(r.Header)
+	if !ok {
+		writeError(w, http.StatusBadRequest, errors.New("unknown webhook provider"))
+		return
+	}
+
+	if !s.verify(p, r.Header, body) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid webhook signature"))
+		return
+	}
+
+	items, err := p.extract(r.Header, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report := s.lint(items)
+	report.Provider = p.name
+	writeJSON(w, http.StatusOK, report)
+}
+
+// verify checks the signature (or the token, for GitLab) of the webhook payload
+// against the configured secret.
+func (s *server) verify(p provider, header http.Header, body []byte) bool {
+	if s.secret == "" {
+		return true
+	}
+
+	signature := header.Get(p.signatureHeader)
+	if p.plainToken {
+		return subtle.ConstantTimeCompare([]byte(signature), []byte(s.secret)) == 1
+	}
+
+	signature = strings.TrimPrefix(signature, "sha256=")
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// item is a single commit message or pull request title to be linted.
+type item struct {
+	kind, id, message string
+}
+
+// lint parses and validates every item and collects the results into a report.
+func (s *server) lint(items []item) Report {
+	v := validator.NewValidatorWithConfig(s.config)
+	report := Report{Valid: true, Results: []Result{}}
+
+	for _, it := range items {
+		result := Result{
+			Kind:        it.kind,
+			ID:          it.id,
+			Message:     v.Redact(it.message),
+			Diagnostics: []validator.Diagnostic{},
+		}
+
+		// A pull request title is only checked by the rules validating the header,
+		// since it can not carry the body and the footers other rules may require
+		msg, err := parser.ParseCommitMessage(it.message)
+		switch {
+		case err != nil:
+			result.Error = v.Redact(err.Error())
+		case it.kind == "pull_request_title":
+			result.Diagnostics = v.LintHeader(msg)
+		default:
+			result.Diagnostics = v.Lint(msg)
+		}
+		result.Valid = err == nil && !validator.HasErrors(result.Diagnostics)
+
+		report.Valid = report.Valid && result.Valid
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// writeJSON writes v as the JSON response body with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes err as a JSON error response with the given status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// provider describes how the webhooks of a Git hosting service are identified,
+// authenticated and decoded.
+type provider struct {
+	name            string
+	eventHeader     string // Header holding the name of the event
+	signatureHeader string // Header holding the signature of the payload
+	plainToken      bool   // Whether the signature header holds the secret as is
+
+	// extract returns the items to be linted from a payload of the given event
+	extract func(header http.Header, body []byte) ([]item, error)
+}
+
+// providers lists the supported Git hosting services. Gitea is checked before GitHub
+// since it also sends the GitHub event headers for compatibility.
+var providers = []provider{
+	{
+		name:            "gitea",
+		eventHeader:     "X-Gitea-Event",
+		signatureHeader: "X-Gitea-Signature",
+		extract:         extractGitHub("X-Gitea-Event"),
+	},
+	{
+		name:            "github",
+		eventHeader:     "X-GitHub-Event",
+		signatureHeader: "X-Hub-Signature-256",
+		extract:         extractGitHub("X-GitHub-Event"),
+	},
+	{
+		name:            "gitlab",
+		eventHeader:     "X-Gitlab-Event",
+		signatureHeader: "X-Gitlab-Token",
+		plainToken:      true,
+		extract:         extractGitLab,
+	},
+}
+
+// detectProvider identifies the provider which sent the webhook from its headers.
+func detectProvider(header http.Header) (provider, bool) {
+	for _, p := range providers {
+		if header.Get(p.eventHeader) != "" {
+			return p, true
+		}
+	}
+	return provider{}, false
+}
+
+// pushPayload is the subset of a push event payload common to all the providers.
+type pushPayload struct {
+	Commits []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"commits"`
+}
+
+// items returns the commit messages of the push event.
+func (p pushPayload) items() []item {
+	items := []item{}
+	for _, c := range p.Commits {
+		items = append(items, item{kind: "commit", id: c.ID, message: c.Message})
+	}
+	return items
+}
+
+// extractGitHub returns the extractor of GitHub payloads (which Gitea payloads are
+// compatible with) whose event name is sent in eventHeader.
+func extractGitHub(eventHeader string) func(http.Header, []byte) ([]item, error) {
+	return func(header http.Header, body []byte) ([]item, error) {
+		switch event := header.Get(eventHeader); event {
+		case "push":
+			var payload pushPayload
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, fmt.Errorf("invalid push payload: %w", err)
+			}
+			return payload.items(), nil
+		case "pull_request":
+			var payload struct {
+				PullRequest struct {
+					Number int    `json:"number"`
+					Title  string `json:"title"`
+				} `json:"pull_request"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return nil, fmt.Errorf("invalid pull request payload: %w", err)
+			}
+			return []item{{
+				kind:    "pull_request_title",
+				id:      fmt.Sprintf("#%d", payload.PullRequest.Number),
+				message: payload.PullRequest.Title,
+			}}, nil
+		case "ping":
+			return []item{}, nil
+		default:
+			return nil, fmt.Errorf("unsupported event: %q", event)
+		}
+	}
+}
+
+// extractGitLab returns the items to be linted from a GitLab webhook payload.
+func extractGitLab(header http.Header, body []byte) ([]item, error) {
+	switch event := header.Get("X-Gitlab-Event"); event {
+	case "Push Hook":
+		var payload pushPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, fmt.Errorf("invalid push payload: %w", err)
+		}
+		return payload.items(), nil
+	case "Merge Request Hook":
+		var payload struct {
+			ObjectAttributes struct {
+				IID   int    `json:"iid"`
+				Title string `json:"title"`
+			} `json:"object_attributes"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, fmt.Errorf("invalid merge request payload: %w", err)
+		}
+		return []item{{
+			kind:    "pull_request_title",
+			id:      fmt.Sprintf("!%d", payload.ObjectAttributes.IID),
+			message: payload.ObjectAttributes.Title,
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event: %q", event)
+	}
+}
@@ -0,0 +1,179 @@
+// Package suggest proposes the type and scope of a commit from the files it changes
+// using deterministic heuristics, e.g. a commit changing only documentation files is
+// a "docs" commit.
+package suggest
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// Classifier classifies the changed files and derives their scope, as implemented by
+// the validator.
+type Classifier interface {
+	Classify(file string) string
+	SuggestScope(paths []string) string
+}
+
+// Candidate is a suggested commit header along with the reasons for it. The higher
+// the score of a candidate, the more likely it is to be the right one.
+type Candidate struct {
+	Type    string   `json:"type"`
+	Scope   string   `json:"scope,omitempty"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// Header returns the header of the candidate without a description, e.g.
+// "fix(parser): ".
+func (c Candidate) Header() string {
+	if c.Scope == "" {
+		return c.Type + ": "
+	}
+	return c.Type + "(" + c.Scope + "): "
+}
+
+// typeOrder breaks the ties between the candidates with the same score.
+var typeOrder = []string{
+	"feat",
+	"fix",
+	"docs",
+	"test",
+	"ci",
+	"build",
+	"refactor",
+	"perf",
+	"style",
+	"chore",
+}
+
+// stats holds the changed files grouped by their class and by how they were changed.
+type stats struct {
+	total   int
+	byClass map[string][]git.Change
+}
+
+// count returns the number of files of the class changed with any of the statuses
+// (or with any status if none are given).
+func (s stats) count(class string, statuses ...string) int {
+	n := 0
+	for _, c := range s.byClass[class] {
+		if len(statuses) == 0 || slices.Contains(statuses, c.Status) {
+			n++
+		}
+	}
+	return n
+}
+
+// only reports whether all the changed files are of the class.
+func (s stats) only(class string) bool {
+	return s.total > 0 && s.count(class) == s.total
+}
+
+// plural returns "<n> <noun>" with the noun in plural form unless n is one.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Suggest returns the candidate headers for a commit with the given changes, ranked
+// from the most to the least likely. A "chore" candidate is always included as a
+// fallback.
+func Suggest(c Classifier, changes []git.Change) []Candidate {
+	s := stats{total: len(changes), byClass: map[string][]git.Change{}}
+	paths, sources := []string{}, []string{}
+	goMod := false
+	for _, change := range changes {
+		class := c.Classify(change.Path)
+		s.byClass[class] = append(s.byClass[class], change)
+		paths = append(paths, change.Path)
+		if class == validator.ClassSource {
+			sources = append(sources, change.Path)
+		}
+		if name := path.Base(change.Path); name == "go.mod" || name == "go.sum" {
+			goMod = true
+		}
+	}
+
+	candidates := map[string]*Candidate{}
+	add := func(typ string, score int, reason string) {
+		if candidates[typ] == nil {
+			candidates[typ] = &Candidate{Type: typ, Reasons: []string{}}
+		}
+		candidates[typ].Score += score
+		candidates[typ].Reasons = append(candidates[typ].Reasons, reason)
+	}
+
+	switch {
+	case s.total == 0:
+	case s.only(validator.ClassDocs):
+		add("docs", 100, "only documentation files are changed")
+	case s.only(validator.ClassTest):
+		if added := s.count(validator.ClassTest, "A"); added > 0 {
+			add("test", 100, "adds "+plural(added, "test file"))
+		} else {
+			add("test", 90, "only test files are changed")
+		}
+	case s.only(validator.ClassCI):
+		add("ci", 100, "only CI configuration files are changed")
+	case s.only(validator.ClassBuild):
+		if goMod {
+			add("build", 100, "updates the Go module dependencies")
+		} else {
+			add("build", 90, "only build files are changed")
+		}
+		add("chore", 20, "dependency updates are sometimes considered chores")
+	case len(sources) == 0:
+		add("chore", 40, "no source files are changed")
+	default:
+		if added := s.count(validator.ClassSource, "A"); added > 0 {
+			add("feat", 60, "adds "+plural(added, "source file"))
+		}
+		if modified := s.count(validator.ClassSource, "M", "T"); modified > 0 {
+			add("fix", 50, "modifies "+plural(modified, "existing source file"))
+			add("refactor", 30, "modifies "+plural(modified, "existing source file"))
+		}
+		if deleted := s.count(validator.ClassSource, "D"); deleted > 0 {
+			add("refactor", 40, "removes "+plural(deleted, "source file"))
+		}
+		if added := s.count(validator.ClassTest, "A"); added > 0 {
+			add("feat", 10, "adds "+plural(added, "test file")+" for the changes")
+			add("fix", 10, "adds "+plural(added, "test file")+" for the changes")
+		}
+		if modified := s.count(validator.ClassTest, "M"); modified > 0 {
+			add("fix", 10, "updates "+plural(modified, "existing test file"))
+		}
+		if goMod {
+			add("build", 30, "updates the Go module dependencies")
+		}
+	}
+	add("chore", 10, "fallback for changes not fitting any other type")
+
+	// Code changes are scoped to the sources, the other ones to all the files
+	scope := c.SuggestScope(paths)
+	if len(sources) > 0 {
+		scope = c.SuggestScope(sources)
+	}
+
+	ranked := []Candidate{}
+	for _, candidate := range candidates {
+		if scope != candidate.Type {
+			candidate.Scope = scope
+		}
+		ranked = append(ranked, *candidate)
+	}
+	slices.SortFunc(ranked, func(a, b Candidate) int {
+		if a.Score != b.Score {
+			return b.Score - a.Score
+		}
+		return slices.Index(typeOrder, a.Type) - slices.Index(typeOrder, b.Type)
+	})
+
+	return ranked
+}
@@ -0,0 +1,100 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/git"
+	"github.com/Weburz/crisp/internal/validator"
+)
+
+// headers returns the headers of the candidates in their order.
+func headers(candidates []Candidate) []string {
+	got := []string{}
+	for _, c := range candidates {
+		got = append(got, c.Header())
+	}
+	return got
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []git.Change
+		want    []string
+	}{
+		{
+			name: "documentation only",
+			changes: []git.Change{
+				{Status: "M", Path: "README.md"},
+				{Status: "A", Path: "CONTRIBUTING.md"},
+			},
+			want: []string{"docs: ", "chore: "},
+		},
+		{
+			name:    "new test file",
+			changes: []git.Change{{Status: "A", Path: "internal/git/git_test.go"}},
+			want:    []string{"test(git): ", "chore(git): "},
+		},
+		{
+			name: "dependency update",
+			changes: []git.Change{
+				{Status: "M", Path: "go.mod"},
+				{Status: "M", Path: "go.sum"},
+			},
+			want: []string{"build: ", "chore: "},
+		},
+		{
+			name: "new source file with tests",
+			changes: []git.Change{
+				{Status: "A", Path: "internal/suggest/suggest.go"},
+				{Status: "A", Path: "internal/suggest/suggest_test.go"},
+				{Status: "M", Path: "README.md"},
+			},
+			want: []string{"feat(suggest): ", "fix(suggest): ", "chore(suggest): "},
+		},
+		{
+			name: "modified source file",
+			changes: []git.Change{
+				{Status: "M", Path: "internal/parser/parser.go"},
+				{Status: "M", Path: "internal/parser/parser_test.go"},
+			},
+			want: []string{"fix(parser): ", "refactor(parser): ", "chore(parser): "},
+		},
+		{
+			name: "removed source file",
+			changes: []git.Change{
+				{Status: "D", Path: "internal/reader/stdin.go"},
+				{Status: "M", Path: "internal/reader/reader.go"},
+			},
+			want: []string{"refactor(reader): ", "fix(reader): ", "chore(reader): "},
+		},
+		{
+			name:    "nothing staged",
+			changes: []git.Change{},
+			want:    []string{"chore: "},
+		},
+	}
+
+	v := validator.NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := headers(Suggest(v, tt.changes))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest_Reasons(t *testing.T) {
+	candidates := Suggest(validator.NewValidator(), []git.Change{
+		{Status: "A", Path: "internal/git/git_test.go"},
+		{Status: "A", Path: "internal/git/testdata/a.txt"},
+	})
+
+	want := []string{"adds 2 test files"}
+	if got := candidates[0].Reasons; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reasons = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,216 @@
+// Package textwidth measures text the way readers perceive it, either in grapheme
+// clusters (user-perceived characters) or in the columns it occupies in a terminal,
+// where East Asian wide characters and emoji take two columns.
+//
+// The segmentation follows the rules of Unicode Standard Annex #29 closely enough
+// for the text of commit messages (combining marks, emoji sequences, flags and Hangul
+// syllables) without depending on the full Unicode property tables.
+package textwidth
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The units text can be measured in.
+const (
+	Columns   = "columns"
+	Graphemes = "graphemes"
+)
+
+// Units lists the units text can be measured in.
+var Units = []string{Columns, Graphemes}
+
+// Cluster is a grapheme cluster found at the byte range [Start, End) of the text,
+// along with the number of columns it occupies.
+type Cluster struct {
+	Start int
+	End   int
+	Width int
+}
+
+// Size returns the size of the cluster in the given unit.
+func (c Cluster) Size(unit string) int {
+	if unit == Graphemes {
+		return 1
+	}
+	return c.Width
+}
+
+// zwj is the zero width joiner gluing emoji into a single one, e.g. "👩‍💻".
+const zwj = '\u200d'
+
+// isExtend reports whether r extends the cluster preceding it instead of starting a
+// new one, like combining marks, variation selectors and emoji skin tone modifiers.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zwj ||
+		(r >= 0xfe00 && r <= 0xfe0f) ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f) ||
+		(r >= 0xe0100 && r <= 0xe01ef)
+}
+
+// isRegionalIndicator reports whether r is one of the letters of which pairs make up
+// flags, e.g. "🇩🇪".
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isPictographic reports whether r is an emoji (or a symbol which can be joined into
+// one).
+func isPictographic(r rune) bool {
+	return (r >= 0x1f000 && r <= 0x1faff) ||
+		(r >= 0x2600 && r <= 0x27bf) ||
+		(r >= 0x2300 && r <= 0x23ff) ||
+		(r >= 0x2b00 && r <= 0x2bff)
+}
+
+// hangul classifies r as a leading consonant ("L"), a vowel ("V"), a trailing
+// consonant ("T") or a precomposed syllable ("S") of the Hangul script.
+func hangul(r rune) byte {
+	switch {
+	case (r >= 0x1100 && r <= 0x115f) || (r >= 0xa960 && r <= 0xa97c):
+		return 'L'
+	case (r >= 0x1160 && r <= 0x11a7) || (r >= 0xd7b0 && r <= 0xd7c6):
+		return 'V'
+	case (r >= 0x11a8 && r <= 0x11ff) || (r >= 0xd7cb && r <= 0xd7fb):
+		return 'T'
+	case r >= 0xac00 && r <= 0xd7a3:
+		return 'S'
+	}
+	return 0
+}
+
+// joins reports whether r continues the cluster whose last rune is prev and whose
+// first rune is first.
+func joins(first, prev, r rune, flags int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case isExtend(r):
+		return first != '\n' && first != '\r'
+	case prev == zwj && isPictographic(first) && isPictographic(r):
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return flags%2 == 1
+	}
+
+	switch hangul(prev) {
+	case 'L':
+		return hangul(r) != 0
+	case 'V', 'S':
+		return hangul(r) == 'V' || hangul(r) == 'T'
+	case 'T':
+		return hangul(r) == 'T'
+	}
+	return false
+}
+
+// Split segments s into its grapheme clusters.
+func Split(s string) []Cluster {
+	clusters := []Cluster{}
+
+	var first, prev rune
+	flags := 0
+	for idx := 0; idx < len(s); {
+		r, size := utf8.DecodeRuneInString(s[idx:])
+
+		if len(clusters) > 0 && joins(first, prev, r, flags) {
+			c := &clusters[len(clusters)-1]
+			c.End = idx + size
+			if r == 0xfe0f || isRegionalIndicator(r) {
+				c.Width = 2 // Emoji presentation
+			}
+		} else {
+			clusters = append(clusters, Cluster{
+				Start: idx,
+				End:   idx + size,
+				Width: runeWidth(r),
+			})
+			first, flags = r, 0
+		}
+
+		if isRegionalIndicator(r) {
+			flags++
+		}
+		prev = r
+		idx += size
+	}
+
+	return clusters
+}
+
+// Measure returns the length of s in the given unit.
+func Measure(s, unit string) int {
+	length := 0
+	for _, c := range Split(s) {
+		length += c.Size(unit)
+	}
+	return length
+}
+
+// Width returns the number of columns s occupies in a terminal.
+func Width(s string) int {
+	return Measure(s, Columns)
+}
+
+// Truncate returns the byte offset of s up to which its length in the given unit does
+// not exceed limit, i.e. where s is cut off to fit the limit.
+func Truncate(s, unit string, limit int) int {
+	length := 0
+	for _, c := range Split(s) {
+		length += c.Size(unit)
+		if length > limit {
+			return c.Start
+		}
+	}
+	return len(s)
+}
+
+// runeWidth returns the number of columns the rune occupies when starting a cluster.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.IsControl(r):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// wide lists the ranges of the characters which are wide or fullwidth according to
+// the East Asian Width property, including the emoji presented as such by default.
+var wide = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18aff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f251}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
+
+// isWide reports whether r is a wide or fullwidth character.
+func isWide(r rune) bool {
+	idx := sort.Search(len(wide), func(i int) bool { return wide[i][1] >= r })
+	return idx < len(wide) && wide[idx][0] <= r
+}
@@ -0,0 +1,77 @@
+package textwidth
+
+import "testing"
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		text      string
+		graphemes int
+		columns   int
+	}{
+		{"fix: handle it", 14, 14},
+		{"café", 4, 4},
+		{"cafe\u0301", 4, 4},
+		{"修复解析器", 5, 10},
+		{"한국어", 3, 6},
+		{"\u1100\u1161\u11a8", 1, 2},
+		{"fix 🐛", 5, 6},
+		{"👩\u200d💻", 1, 2},
+		{"👍🏽", 1, 2},
+		{"🇩🇪🇫🇷", 2, 4},
+		{"❤️", 1, 2},
+		{"ｆｉｘ", 3, 6},
+		{"\xffx", 2, 2},
+		{"", 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := Measure(tt.text, Graphemes); got != tt.graphemes {
+			t.Errorf("Measure(%q, Graphemes) = %d, want %d", tt.text, got, tt.graphemes)
+		}
+		if got := Width(tt.text); got != tt.columns {
+			t.Errorf("Width(%q) = %d, want %d", tt.text, got, tt.columns)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	clusters := Split("e\u0301🇩🇪x")
+
+	want := []Cluster{{0, 3, 1}, {3, 11, 2}, {11, 12, 1}}
+	if len(clusters) != len(want) {
+		t.Fatalf("Split() = %v, want %v", clusters, want)
+	}
+	for idx := range want {
+		if clusters[idx] != want[idx] {
+			t.Errorf("cluster %d = %v, want %v", idx, clusters[idx], want[idx])
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		unit  string
+		limit int
+		want  int
+	}{
+		{"abcdef", Columns, 4, 4},
+		{"abc", Columns, 4, 3},
+		{"修复解析器", Columns, 5, 6},
+		{"修复解析器", Graphemes, 4, 12},
+		{"cafés", Graphemes, 4, 6},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.text, tt.unit, tt.limit); got != tt.want {
+			t.Errorf(
+				"Truncate(%q, %s, %d) = %d, want %d",
+				tt.text,
+				tt.unit,
+				tt.limit,
+				got,
+				tt.want,
+			)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// urlPattern matches URLs, which can not be wrapped without breaking them.
+var urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://\S+`)
+
+// trailerPattern matches the lines formatted as Git trailers (e.g. "Signed-off-by:
+// Jane Doe <jane@example.com>") or as footers referencing an issue (e.g. "Refs #12").
+var trailerPattern = regexp.MustCompile(
+	`^(?:BREAKING CHANGE|[A-Za-z][A-Za-z0-9-]*)(?:: | #)\S`,
+)
+
+// bodyLines returns the lines of the commit message following the header. The empty
+// line after the final newline of the message is left out. Returns nil if the message
+// was not parsed from its textual form.
+func bodyLines(msg *parser.CommitMessage) []parser.Line {
+	if len(msg.Lines) < 2 {
+		return nil
+	}
+
+	lines := msg.Lines[1:]
+	if lines[len(lines)-1].Text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// codeLines returns the numbers of the lines which are part of a code block, either
+// fenced (with "```" or "~~~") or indented by four spaces or a tab.
+func codeLines(lines []parser.Line) map[int]bool {
+	code := map[int]bool{}
+
+	fence := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line.Text)
+		switch {
+		case fence != "":
+			code[line.Number] = true
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			code[line.Number] = true
+			fence = trimmed[:3]
+		case trimmed != "" && (strings.HasPrefix(line.Text, "    ") ||
+			strings.HasPrefix(line.Text, "\t")):
+			code[line.Number] = true
+		}
+	}
+
+	return code
+}
+
+// checkBodySeparator reports a header which is not followed by a blank line.
+func checkBodySeparator(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	lines := bodyLines(msg)
+	if len(lines) == 0 || lines[0].Kind == parser.LineBlank {
+		return nil
+	}
+
+	return []Diagnostic{{
+		Message: "the header must be separated from the body by a blank line",
+		Line:    lines[0].Number,
+		Span:    &parser.Span{Start: 0, End: len(lines[0].Text)},
+		Fix: &Fix{
+			Title:   "Insert a blank line after the header",
+			Line:    lines[0].Number,
+			Span:    parser.Span{Start: 0, End: 0},
+			NewText: "\n",
+		},
+	}}
+}
+
+// checkBodyBlankLines reports the superfluous blank lines at the start of the body
+// (after the one separating it from the header) and at the end of the message.
+func checkBodyBlankLines(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	lines := bodyLines(msg)
+
+	first, last := -1, -1
+	for idx, line := range lines {
+		if line.Kind != parser.LineBlank {
+			if first < 0 {
+				first = idx
+			}
+			last = idx
+		}
+	}
+
+	diagnostics := []Diagnostic{}
+	blank := func(line parser.Line, where string) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf(
+				"line %d is a blank line at the %s",
+				line.Number,
+				where,
+			),
+			Line: line.Number,
+		})
+	}
+
+	if first > 0 {
+		for _, line := range lines[1:first] {
+			blank(line, "start of the body")
+		}
+	}
+	for _, line := range lines[last+1:] {
+		blank(line, "end of the message")
+	}
+
+	return diagnostics
+}
+
+// checkBodyLineLength reports the lines of the body which are longer than the
+// configured limit. Lines containing a URL, code blocks and trailers are skipped
+// since they can not be wrapped.
+func checkBodyLineLength(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	limit := v.config.Body.MaxLineLength
+	lines := bodyLines(msg)
+	code := codeLines(lines)
+
+	diagnostics := []Diagnostic{}
+	for _, line := range lines {
+		text := strings.TrimRightFunc(line.Text, unicode.IsSpace)
+		if line.Kind == parser.LineFooter || line.Kind == parser.LineIgnored ||
+			code[line.Number] || trailerPattern.MatchString(text) ||
+			urlPattern.MatchString(text) {
+			continue
+		}
+
+		length := utf8.RuneCountInString(text)
+		if length <= limit {
+			continue
+		}
+
+		// The span covers the characters past the limit
+		start := 0
+		for count := 0; count < limit; count++ {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			start += size
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf(
+				"line %d is %d characters long but the limit is %d",
+				line.Number,
+				length,
+				limit,
+			),
+			Line: line.Number,
+			Span: &parser.Span{Start: start, End: len(text)},
+		})
+	}
+
+	return diagnostics
+}
+
+// checkTrailingWhitespace reports the lines of the commit message which end with
+// whitespace.
+func checkTrailingWhitespace(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, line := range msg.Lines {
+		text := strings.TrimRightFunc(line.Text, unicode.IsSpace)
+		if text == line.Text {
+			continue
+		}
+
+		span := parser.Span{Start: len(text), End: len(line.Text)}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("line %d has trailing whitespace", line.Number),
+			Line:     line.Number,
+			Span:     &span,
+			Fix: &Fix{
+				Title:   "Remove the trailing whitespace",
+				Line:    line.Number,
+				Span:    span,
+				NewText: "",
+			},
+		})
+	}
+
+	return diagnostics
+}
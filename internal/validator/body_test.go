@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// lines returns the line numbers of the diagnostics.
+func lines(diagnostics []Diagnostic) []int {
+	numbers := []int{}
+	for _, d := range diagnostics {
+		numbers = append(numbers, d.Line)
+	}
+	return numbers
+}
+
+func TestBodySeparator(t *testing.T) {
+	v := NewValidator()
+
+	message := "fix: handle it\nThe body."
+	d := lintRule(t, v, "body-separator", message, nil)
+	if len(d) != 1 || d[0].Line != 2 || d[0].Severity != SeverityError {
+		t.Fatalf("expected an error at line 2, got %v", d)
+	}
+
+	want := "fix: handle it\n\nThe body."
+	if got := ApplyFix(message, *d[0].Fix); got != want {
+		t.Errorf("ApplyFix() = %q, want %q", got, want)
+	}
+
+	for _, message := range []string{"fix: handle it", "fix: handle it\n"} {
+		if d := lintRule(t, v, "body-separator", message, nil); len(d) != 0 {
+			t.Errorf("expected no diagnostics for %q, got %v", message, d)
+		}
+	}
+}
+
+func TestBodyBlankLines(t *testing.T) {
+	tests := map[string][]int{
+		"fix: handle it\n":                       {},
+		"fix: handle it\n\nThe body.\n":          {},
+		"fix: handle it\n\n\n\nThe body.\n":      {3, 4},
+		"fix: handle it\n\nThe body.\n\n\n":      {4, 5},
+		"fix: handle it\n\nThe body.\n\nRefs: 1": {},
+		"fix: handle it\n\n":                     {2},
+	}
+
+	v := NewValidator()
+	for message, want := range tests {
+		d := lintRule(t, v, "body-blank-lines", message, nil)
+		if got := lines(d); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected blank lines %v, got %v", message, want, got)
+		}
+	}
+}
+
+func TestBodyLineLength(t *testing.T) {
+	long := strings.Repeat("word ", 15) + "end"
+	message := strings.Join([]string{
+		"docs: wrap it",
+		"",
+		long,
+		"See https://example.com/" + strings.Repeat("x", 80) + " for details.",
+		"",
+		"```",
+		long,
+		"```",
+		"",
+		"    " + long,
+		"",
+		"Signed-off-by: " + long,
+	}, "\n")
+
+	v := NewValidator()
+	d := lintRule(t, v, "body-max-line-length", message, nil)
+	if got := lines(d); !reflect.DeepEqual(got, []int{3}) {
+		t.Fatalf("expected a diagnostic at line 3, got %v", d)
+	}
+	if d[0].Span == nil || long[d[0].Span.Start:d[0].Span.End] != "rd end" {
+		t.Errorf("expected the span to cover the overflow, got %+v", d[0].Span)
+	}
+	if d[0].Severity != SeverityWarning {
+		t.Errorf("expected a warning, got %s", d[0].Severity)
+	}
+
+	// The characters are counted instead of the bytes
+	if d := lintRule(t, v, "body-max-line-length", "docs: wrap it\n\n"+
+		strings.Repeat("é", 72), nil); len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+
+	cfg := config.Default()
+	cfg.Body.MaxLineLength = 100
+	v = NewValidatorWithConfig(cfg)
+	if d := lintRule(t, v, "body-max-line-length", message, nil); len(d) != 0 {
+		t.Errorf("expected no diagnostics with a limit of 100, got %v", d)
+	}
+}
+
+func TestTrailingWhitespace(t *testing.T) {
+	message := "fix: handle it \n\nThe body.\t\n\nRefs: #1"
+
+	d := lintRule(t, NewValidator(), "trailing-whitespace", message, nil)
+	if got := lines(d); !reflect.DeepEqual(got, []int{1, 3}) {
+		t.Fatalf("expected diagnostics at lines 1 and 3, got %v", d)
+	}
+
+	fixed := message
+	for _, diagnostic := range d {
+		fixed = ApplyFix(fixed, *diagnostic.Fix)
+	}
+	if want := "fix: handle it\n\nThe body.\n\nRefs: #1"; fixed != want {
+		t.Errorf("fixed message = %q, want %q", fixed, want)
+	}
+}
+
+func TestBodyRules_Unparsed(t *testing.T) {
+	msg := &parser.CommitMessage{Type: "fix", Description: "handle it", Body: "x "}
+
+	for _, d := range NewValidator().Lint(msg) {
+		t.Errorf("unexpected diagnostic: %v", d)
+	}
+}
@@ -0,0 +1,160 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// typePlaceholder is expanded into the allowed commit types in the branch patterns.
+const typePlaceholder = "{type}"
+
+// compileBranchPatterns compiles the branch name patterns, expanding the "{type}"
+// placeholder into a named group matching any of the allowed commit types.
+func compileBranchPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	types := "(?P<type>" + strings.Join(validTypes, "|") + ")"
+
+	compiled := []*regexp.Regexp{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(strings.ReplaceAll(pattern, typePlaceholder, types))
+		if err != nil {
+			return nil, fmt.Errorf("invalid branch pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+// LintBranch validates the name of a branch against the configured patterns and
+// returns the diagnostics of the violations found, with the severity configured for
+// the "branch-name" rule. Branches matching any of the exempted patterns are not
+// validated, and neither are any of them if the rule is turned off.
+func (v *validator) LintBranch(name string) ([]Diagnostic, error) {
+	severity, configured := v.config.Rules["branch-name"]
+	if severity == "off" {
+		return []Diagnostic{}, nil
+	}
+	if !configured {
+		severity = string(SeverityError)
+	}
+
+	exempt, err := compileBranchPatterns(v.config.Branch.Exempt)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := compileBranchPatterns(v.config.Branch.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, re := range append(exempt, patterns...) {
+		if re.MatchString(name) {
+			return []Diagnostic{}, nil
+		}
+	}
+
+	message := fmt.Sprintf(
+		"branch name %q does not match any of the allowed patterns: %s",
+		name,
+		strings.Join(v.config.Branch.Patterns, ", "),
+	)
+
+	// Point out the most likely cause of the mismatch, a prefix which is not a type
+	usesTypes := slices.ContainsFunc(v.config.Branch.Patterns, func(p string) bool {
+		return strings.Contains(p, typePlaceholder)
+	})
+	if prefix, _, found := strings.Cut(name, "/"); found && usesTypes &&
+		v.isValidType(prefix) != nil {
+		message = fmt.Sprintf(
+			"branch name %q does not start with a valid type, expected one of: %s",
+			name,
+			strings.Join(validTypes, ", "),
+		)
+	}
+
+	return []Diagnostic{{
+		Rule:     "branch-name",
+		Severity: Severity(severity),
+		Message:  message,
+	}}, nil
+}
+
+// BranchInfo holds the information encoded in a branch name, used to pre-fill the
+// commit messages of the branch.
+type BranchInfo struct {
+	Type  string // Commit type the branch name starts with
+	Scope string // Scope matched by a "scope" named group of the pattern
+	Issue string // Issue key, e.g. "ABC-123" or "#123"
+	Topic string // Remainder of the branch name, e.g. "reader-stdin"
+}
+
+// issuePrefix matches an issue key at the start of the topic of a branch, either a
+// Jira-style key (e.g. "ABC-123") or the number of an issue (e.g. "123").
+var issuePrefix = regexp.MustCompile(`^(?:([A-Z][A-Z0-9]+-\d+)|#?(\d+))(?:[-_/.]|$)`)
+
+// ParseBranch extracts the type, issue key and topic from the branch name using the
+// first configured pattern it matches. The "scope" and "issue" named groups of the
+// pattern are used if present, otherwise the issue key is recognised at the start of
+// the remainder of the branch name. Returns nil if the name matches no pattern or is
+// exempted.
+func (v *validator) ParseBranch(name string) (*BranchInfo, error) {
+	exempt, err := compileBranchPatterns(v.config.Branch.Exempt)
+	if err != nil {
+		return nil, err
+	}
+	patterns, err := compileBranchPatterns(v.config.Branch.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, re := range exempt {
+		if re.MatchString(name) {
+			return nil, nil
+		}
+	}
+
+	for _, re := range patterns {
+		match := re.FindStringSubmatchIndex(name)
+		if match == nil {
+			continue
+		}
+
+		// The topic is whatever follows the last of the named groups
+		info, end := &BranchInfo{}, 0
+		for idx, group := range re.SubexpNames() {
+			start, stop := match[2*idx], match[2*idx+1]
+			if start < 0 {
+				continue
+			}
+
+			switch group {
+			case "type":
+				info.Type = name[start:stop]
+			case "scope":
+				info.Scope = name[start:stop]
+			case "issue":
+				info.Issue = name[start:stop]
+			default:
+				continue
+			}
+			end = max(end, stop)
+		}
+		info.Topic = strings.TrimLeft(name[end:], "/-_.")
+
+		if info.Issue == "" {
+			if m := issuePrefix.FindStringSubmatch(info.Topic); m != nil {
+				info.Issue = m[1]
+				if m[2] != "" {
+					info.Issue = "#" + m[2]
+				}
+				info.Topic = strings.TrimLeft(info.Topic[len(m[0]):], "/-_.")
+			}
+		}
+
+		return info, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,149 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+)
+
+func TestLintBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"feat/parser-bang-support", true},
+		{"fix/ABC-123-stdin", true},
+		{"main", true},
+		{"release/v1.2.0", true},
+		{"dependabot/go_modules/github.com/spf13/cobra-1.9.0", true},
+		{"parser-bang-support", false},
+		{"feature/parser-bang-support", false},
+		{"Feat/parser-bang-support", false},
+		{"fix/", false},
+		{"fix/-stdin", false},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		diagnostics, err := v.LintBranch(tt.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if valid := len(diagnostics) == 0; valid != tt.valid {
+			t.Errorf("LintBranch(%q): expected valid=%v, got %v", tt.name, tt.valid,
+				diagnostics)
+		}
+	}
+}
+
+func TestLintBranch_Examples(t *testing.T) {
+	r, _ := LookupRule("branch-name")
+	v := NewValidator()
+
+	for _, example := range r.Good {
+		if diagnostics, _ := v.LintBranch(example); len(diagnostics) != 0 {
+			t.Errorf("good example %q was rejected", example)
+		}
+	}
+	for _, example := range r.Bad {
+		if diagnostics, _ := v.LintBranch(example); len(diagnostics) == 0 {
+			t.Errorf("bad example %q was accepted", example)
+		}
+	}
+}
+
+func TestLintBranch_Diagnostic(t *testing.T) {
+	diagnostics, _ := NewValidator().LintBranch("feature/login")
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diagnostics))
+	}
+
+	d := diagnostics[0]
+	if d.Rule != "branch-name" || d.Severity != SeverityError {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+	if !strings.Contains(d.Message, "does not start with a valid type") {
+		t.Errorf("unexpected message: %q", d.Message)
+	}
+}
+
+func TestLintBranch_Config(t *testing.T) {
+	cfg := config.Default()
+	cfg.Branch.Patterns = []string{`^[A-Z]+-\d+/{type}$`}
+	v := NewValidatorWithConfig(cfg)
+
+	if diagnostics, _ := v.LintBranch("ABC-123/fix"); len(diagnostics) != 0 {
+		t.Errorf("expected branch to be valid, got %v", diagnostics)
+	}
+	if diagnostics, _ := v.LintBranch("fix/ABC-123"); len(diagnostics) != 1 ||
+		strings.Contains(diagnostics[0].Message, "valid type") {
+		t.Errorf("expected a pattern mismatch, got %v", diagnostics)
+	}
+
+	cfg.Branch.Patterns = []string{`^(`}
+	if _, err := v.LintBranch("fix/x"); err == nil {
+		t.Error("expected error for invalid pattern, got nil")
+	}
+}
+
+func TestLintBranch_Severity(t *testing.T) {
+	cfg := config.Default()
+	v := NewValidatorWithConfig(cfg)
+
+	cfg.Rules["branch-name"] = "warning"
+	diagnostics, _ := v.LintBranch("feature/login")
+	if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityWarning {
+		t.Errorf("expected a warning, got %v", diagnostics)
+	}
+
+	cfg.Rules["branch-name"] = "off"
+	if diagnostics, _ := v.LintBranch("feature/login"); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics with the rule off, got %v", diagnostics)
+	}
+}
+
+func TestParseBranch(t *testing.T) {
+	tests := []struct {
+		name string
+		want *BranchInfo
+	}{
+		{
+			"fix/ABC-123-reader-stdin",
+			&BranchInfo{Type: "fix", Issue: "ABC-123", Topic: "reader-stdin"},
+		},
+		{"feat/42-bang", &BranchInfo{Type: "feat", Issue: "#42", Topic: "bang"}},
+		{"docs/readme", &BranchInfo{Type: "docs", Topic: "readme"}},
+		{"main", nil},
+		{"random-branch", nil},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		got, err := v.ParseBranch(tt.name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseBranch(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseBranch_NamedGroups(t *testing.T) {
+	cfg := config.Default()
+	cfg.Branch.Patterns = []string{
+		`^(?P<issue>[A-Z]+-\d+)/{type}/(?P<scope>[a-z]+)-.+$`,
+	}
+
+	got, err := NewValidatorWithConfig(cfg).ParseBranch("ABC-1/fix/lsp-crash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &BranchInfo{Type: "fix", Scope: "lsp", Issue: "ABC-1", Topic: "crash"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBranch() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/glob"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// The classes the changed files are sorted into.
+const (
+	ClassTest   = "test"
+	ClassDocs   = "docs"
+	ClassCI     = "ci"
+	ClassBuild  = "build"
+	ClassSource = "source"
+)
+
+// Classify returns the class of the file at the path (relative to the root of the
+// repository) according to the configured patterns. The files not matching any of the
+// patterns are source files.
+func (v *validator) Classify(file string) string {
+	for _, class := range config.FileClasses {
+		if glob.MatchAny(v.config.Classes[class], file) {
+			return class
+		}
+	}
+	return ClassSource
+}
+
+// classifyAll groups the paths by their class.
+func (v *validator) classifyAll(paths []string) map[string][]string {
+	classes := map[string][]string{}
+	for _, file := range paths {
+		class := v.Classify(file)
+		classes[class] = append(classes[class], file)
+	}
+	return classes
+}
+
+// contradictingClasses lists the classes of files which a commit of the given type is
+// not expected to change.
+var contradictingClasses = map[string][]string{
+	"docs": {ClassSource, ClassTest, ClassCI, ClassBuild},
+	"test": {ClassSource},
+	"ci":   {ClassSource, ClassTest},
+}
+
+// classTypes maps the classes of files to the type of a commit changing only files
+// of that class.
+var classTypes = map[string]string{
+	ClassTest:  "test",
+	ClassDocs:  "docs",
+	ClassCI:    "ci",
+	ClassBuild: "build",
+}
+
+// evidence lists (at most five of) the paths as the evidence of a diagnostic.
+func evidence(paths []string) string {
+	const limit = 5
+	if len(paths) <= limit {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf(
+		"%s and %d more",
+		strings.Join(paths[:limit], ", "),
+		len(paths)-limit,
+	)
+}
+
+// checkTypeChanges reports a type contradicting the classes of the changed files. The
+// "docs", "test" and "ci" types must not change other kinds of files, while the types
+// of code changes (e.g. "feat" or "fix") must change at least one source file.
+func checkTypeChanges(
+	v *validator,
+	msg *parser.CommitMessage,
+	paths []string,
+) []Diagnostic {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	typ := strings.ToLower(msg.Type)
+	classes := v.classifyAll(paths)
+
+	d := Diagnostic{Severity: SeverityWarning}
+	switch typ {
+	case "docs", "test", "ci":
+		for _, class := range contradictingClasses[typ] {
+			if files, ok := classes[class]; ok {
+				d.Message = fmt.Sprintf(
+					"type %q does not match the changed files, it changes %s files: %s",
+					msg.Type,
+					class,
+					evidence(files),
+				)
+				break
+			}
+		}
+	case "feat", "fix", "perf", "refactor", "style":
+		if _, ok := classes[ClassSource]; ok {
+			return nil
+		}
+
+		d.Message = fmt.Sprintf(
+			"type %q does not match the changed files, none of them are source files",
+			msg.Type,
+		)
+
+		// Suggest the type of the only class of files changed, which is not offered as
+		// a fix since the files can not tell what the change is about for certain
+		if len(classes) == 1 {
+			for class, files := range classes {
+				suggestion := classTypes[class]
+				d.Message = fmt.Sprintf(
+					"type %q does not match the changed files, which are all %s "+
+						"files: %s; did you mean %q?",
+					msg.Type,
+					class,
+					evidence(files),
+					suggestion,
+				)
+			}
+		}
+	}
+
+	if d.Message == "" {
+		return nil
+	}
+	return atHeader([]Diagnostic{d}, msg, msg.Spans.Type)
+}
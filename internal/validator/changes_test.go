@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := map[string]string{
+		"internal/parser/parser_test.go":        ClassTest,
+		"internal/server/testdata/github.json":  ClassTest,
+		"docs/src/content/docs/index.mdx":       ClassDocs,
+		"README.md":                             ClassDocs,
+		".github/workflows/ci.yml":              ClassCI,
+		"go.mod":                                ClassBuild,
+		"Taskfile.yml":                          ClassBuild,
+		"internal/parser/parser.go":             ClassSource,
+		"cmd/root.go":                           ClassSource,
+		"internal/server/testdata/README.md":    ClassTest,
+		"docs/src/content/docs/usage-guide.txt": ClassDocs,
+	}
+
+	v := NewValidator()
+	for file, want := range tests {
+		if got := v.Classify(file); got != want {
+			t.Errorf("Classify(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestTypeChanges(t *testing.T) {
+	tests := []struct {
+		message string
+		paths   []string
+		want    string // Substring of the diagnostic, empty if none is expected
+	}{
+		{"docs: describe it", []string{"README.md", "docs/index.md"}, ""},
+		{
+			"docs: describe it",
+			[]string{"README.md", "cmd/root.go"},
+			"it changes source files: cmd/root.go",
+		},
+		{"test: cover it", []string{"cmd/root.go"}, "it changes source files"},
+		{"test: cover it", []string{"internal/git/git_test.go", "go.mod"}, ""},
+		{"ci: run it", []string{".github/workflows/ci.yml"}, ""},
+		{"fix: handle it", []string{"cmd/root.go", "README.md"}, ""},
+		{
+			"feat: add it",
+			[]string{"README.md", "docs/index.md"},
+			`which are all docs files: README.md, docs/index.md; did you mean "docs"?`,
+		},
+		{
+			"fix: handle it",
+			[]string{"README.md", "internal/git/git_test.go"},
+			"none of them are source files",
+		},
+		{"build: bump it", []string{"go.mod", "cmd/root.go"}, ""},
+		{"chore: tidy it", []string{"README.md"}, ""},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		d := lintRule(t, v, "type-changes", tt.message, tt.paths)
+		if tt.want == "" {
+			if len(d) != 0 {
+				t.Errorf("%q with %q: expected no diagnostics, got %v", tt.message,
+					tt.paths, d)
+			}
+			continue
+		}
+
+		if len(d) != 1 || !strings.Contains(d[0].Message, tt.want) {
+			t.Errorf("%q with %q: expected a diagnostic containing %q, got %v",
+				tt.message, tt.paths, tt.want, d)
+		}
+	}
+}
+
+func TestTypeChanges_Suggestion(t *testing.T) {
+	paths := []string{"internal/git/git_test.go"}
+	d := lintRule(t, NewValidator(), "type-changes", "fix: cover it", paths)
+	if len(d) != 1 || !strings.HasSuffix(d[0].Message, `did you mean "test"?`) {
+		t.Fatalf("expected a diagnostic suggesting the test type, got %v", d)
+	}
+
+	// The type is only suggested, never changed by the fixes
+	if d[0].Fix != nil {
+		t.Errorf("expected no fix, got %+v", d[0].Fix)
+	}
+	got, _ := NewValidator().FixMessage("fix: cover it", paths)
+	if got != "fix: cover it" {
+		t.Errorf("FixMessage() = %q, want %q", got, "fix: cover it")
+	}
+}
+
+func TestTypeChanges_Evidence(t *testing.T) {
+	paths := []string{"a.go", "b.go", "c.go", "d.go", "e.go", "f.go", "g.go"}
+	if got := evidence(paths); got != "a.go, b.go, c.go, d.go, e.go and 2 more" {
+		t.Errorf("evidence() = %q", got)
+	}
+}
+
+func TestPathRules_Examples(t *testing.T) {
+	paths := map[string][]string{
+		"scope-paths":  {"internal/parser/parser.go"},
+		"type-changes": {"internal/config/config.go"},
+	}
+	v := scopePathsValidator(nil)
+
+	for _, r := range Rules() {
+		if r.checkPaths == nil {
+			continue
+		}
+		if _, ok := paths[r.ID]; !ok {
+			t.Fatalf("rule %q has no paths to test its examples with", r.ID)
+		}
+
+		for _, example := range r.Good {
+			if d := lintRule(t, v, r.ID, example, paths[r.ID]); len(d) != 0 {
+				t.Errorf("rule %q: good example %q was rejected", r.ID, example)
+			}
+		}
+		for _, example := range r.Bad {
+			if d := lintRule(t, v, r.ID, example, paths[r.ID]); len(d) == 0 {
+				t.Errorf("rule %q: bad example %q was accepted", r.ID, example)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// Severity indicates how severe a violation of a rule is. Only violations with the
+// "error" severity cause a commit message to be rejected.
+type Severity string
+
+// The severities a diagnostic can be reported with.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic describes a single violation of a rule found in a commit message.
+//
+// The Line (1-based) and the Span (a byte range within that line) locate the
+// violation in the original message, they are unset if the location is unknown. Fix
+// is set if the violation can be corrected automatically.
+type Diagnostic struct {
+	Rule     string       `json:"rule"`
+	Severity Severity     `json:"severity"`
+	Message  string       `json:"message"`
+	Line     int          `json:"line,omitempty"`
+	Span     *parser.Span `json:"span,omitempty"`
+	Fix      *Fix         `json:"fix,omitempty"`
+}
+
+// String renders the diagnostic as "<SEVERITY>[<RULE>]: <MESSAGE>".
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s[%s]: %s", d.Severity, d.Rule, d.Message)
+}
+
+// Fix is an automatic correction of a diagnostic. It replaces the Span of the given
+// Line (1-based) of the commit message with NewText. A Line just past the end of the
+// message appends NewText as a new line.
+type Fix struct {
+	Title   string      `json:"title"`
+	Line    int         `json:"line"`
+	Span    parser.Span `json:"span"`
+	NewText string      `json:"newText"`
+}
+
+// ApplyFix applies the fix to the commit message and returns the corrected message.
+// The message is returned as is if the fix does not apply to it.
+func ApplyFix(message string, fix Fix) string {
+	lines := strings.Split(message, "\n")
+
+	switch {
+	case fix.Line == len(lines)+1:
+		lines = append(lines, fix.NewText)
+	case fix.Line >= 1 && fix.Line <= len(lines):
+		line := lines[fix.Line-1]
+		start, end := fix.Span.Start, fix.Span.End
+		if start < 0 || start > end || end > len(line) {
+			return message
+		}
+		lines[fix.Line-1] = line[:start] + fix.NewText + line[end:]
+	default:
+		return message
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+func TestApplyFix(t *testing.T) {
+	message := "Feat(Parser): Add spans.\n\nBody."
+
+	tests := []struct {
+		name string
+		fix  Fix
+		want string
+	}{
+		{
+			name: "replace span",
+			fix:  Fix{Line: 1, Span: parser.Span{Start: 0, End: 4}, NewText: "feat"},
+			want: "feat(Parser): Add spans.\n\nBody.",
+		},
+		{
+			name: "append line",
+			fix:  Fix{Line: 4, NewText: "Refs: #1"},
+			want: "Feat(Parser): Add spans.\n\nBody.\nRefs: #1",
+		},
+		{
+			name: "out of range line",
+			fix:  Fix{Line: 9, NewText: "nope"},
+			want: message,
+		},
+		{
+			name: "out of range span",
+			fix:  Fix{Line: 3, Span: parser.Span{Start: 2, End: 40}, NewText: "nope"},
+			want: message,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyFix(message, tt.fix); got != tt.want {
+				t.Errorf("ApplyFix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint_Fixes(t *testing.T) {
+	message := "Feat(Parser): Add spans."
+
+	msg, err := parser.ParseCommitMessage(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	diagnostics := NewValidator().Lint(msg)
+	if len(diagnostics) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %d: %v", len(diagnostics), diagnostics)
+	}
+
+	// Apply the fixes from the end of the line so that the spans remain valid
+	fixed := message
+	for idx := len(diagnostics) - 1; idx >= 0; idx-- {
+		d := diagnostics[idx]
+		if d.Line != 1 || d.Span == nil {
+			t.Errorf("expected diagnostic %s to be located on the header", d)
+		}
+		if d.Fix == nil {
+			t.Fatalf("expected diagnostic %s to be fixable", d)
+		}
+		fixed = ApplyFix(fixed, *d.Fix)
+	}
+
+	if want := "feat(parser): add spans"; fixed != want {
+		t.Errorf("expected fixed message %q, got %q", want, fixed)
+	}
+}
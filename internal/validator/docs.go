@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Weburz/crisp/internal/formatter"
+)
+
+// Explain renders the long-form documentation of the rule as plain text suitable for
+// printing to a terminal.
+func (r Rule) Explain() string {
+	var b strings.Builder
+
+	summary := formatter.NewFormatter(78).WrapBody(r.Summary)
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", r.ID, summary)
+	rationale := formatter.NewFormatter(76).WrapBody(r.Rationale)
+	fmt.Fprintf(&b, "Rationale:\n%s\n", indent(rationale, "  "))
+
+	if len(r.Good) > 0 {
+		b.WriteString("\nGood:\n")
+		for _, example := range r.Good {
+			fmt.Fprintf(&b, "%s\n", bullet(example, "  ✔ "))
+		}
+	}
+
+	if len(r.Bad) > 0 {
+		b.WriteString("\nBad:\n")
+		for _, example := range r.Bad {
+			fmt.Fprintf(&b, "%s\n", bullet(example, "  ✘ "))
+		}
+	}
+
+	if r.Optional {
+		b.WriteString("\nThis rule is optional, enable it by setting its severity " +
+			"in the\n\"rules\" setting of the configuration.\n")
+	}
+
+	b.WriteString("\nOptions:\n")
+	if len(r.Options) == 0 {
+		b.WriteString("  This rule has no options.\n")
+	}
+	for _, o := range r.Options {
+		fmt.Fprintf(&b, "  %s (default: %s)\n", o.Name, o.Default)
+		description := formatter.NewFormatter(72).WrapBody(o.Description)
+		fmt.Fprintf(&b, "%s\n", indent(description, "      "))
+	}
+
+	return b.String()
+}
+
+// Markdown renders the documentation of the rule as a section of a Markdown document.
+func (r Rule) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## `%s`\n\n%s\n\n", r.ID, r.Summary)
+	rationale := formatter.NewFormatter(80).WrapBody("**Rationale**: " + r.Rationale)
+	fmt.Fprintf(&b, "%s\n", rationale)
+
+	if r.Optional {
+		b.WriteString("\nThis rule is optional, enable it by setting its severity " +
+			"in the `rules` setting\nof the configuration.\n")
+	}
+
+	if len(r.Good) > 0 {
+		b.WriteString("\n**Good**:\n")
+		b.WriteString(codeBlocks(r.Good))
+	}
+
+	if len(r.Bad) > 0 {
+		b.WriteString("\n**Bad**:\n")
+		b.WriteString(codeBlocks(r.Bad))
+	}
+
+	if len(r.Options) > 0 {
+		b.WriteString("\n**Options**:\n\n")
+		b.WriteString("| Option | Default | Description |\n")
+		b.WriteString("| ------ | ------- | ----------- |\n")
+		// Pipes must be escaped to not end the table cells early (even in code spans)
+		escape := strings.NewReplacer("|", "\\|").Replace
+		for _, o := range r.Options {
+			fmt.Fprintf(
+				&b,
+				"| `%s` | `%s` | %s |\n",
+				o.Name,
+				escape(o.Default),
+				escape(o.Description),
+			)
+		}
+	}
+
+	return b.String()
+}
+
+// rulesPreamble is the front matter and introduction of the rules reference page.
+const rulesPreamble = `---
+title: Rules Reference
+description: The reference of the rules enforced by Crisp
+---
+
+<!-- This file is generated by "task docs:rules", do not edit it by hand! -->
+
+Crisp validates commit messages against the following rules. Run ` + "`crisp rules`" + `
+to list them and ` + "`crisp explain <RULE>`" + ` to read about a rule in the terminal.
+`
+
+// RulesMarkdown renders the documentation of all the rules as the rules reference
+// page of the documentations site.
+func RulesMarkdown() string {
+	var b strings.Builder
+
+	b.WriteString(rulesPreamble)
+	for _, r := range rules {
+		b.WriteString("\n")
+		b.WriteString(r.Markdown())
+	}
+
+	return b.String()
+}
+
+// codeBlocks renders the examples as a Markdown code block. Each example gets a block
+// of its own if any of them spans multiple lines, to tell them apart.
+func codeBlocks(examples []string) string {
+	groups := [][]string{examples}
+	for _, example := range examples {
+		if strings.Contains(example, "\n") {
+			groups = [][]string{}
+			for _, example := range examples {
+				groups = append(groups, []string{example})
+			}
+			break
+		}
+	}
+
+	var b strings.Builder
+	for _, group := range groups {
+		fmt.Fprintf(&b, "\n```text\n%s\n```\n", strings.Join(group, "\n"))
+	}
+	return b.String()
+}
+
+// bullet prefixes the first line of s with marker and indents the following lines
+// to align with it.
+func bullet(s, marker string) string {
+	padding := strings.Repeat(" ", utf8.RuneCountInString(marker))
+
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines[1:] {
+		lines[idx+1] = strings.TrimRight(padding+line, " ")
+	}
+	return marker + strings.Join(lines, "\n")
+}
+
+// indent prefixes each line of s with prefix.
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		lines[idx] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
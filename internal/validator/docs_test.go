@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// rulesPage is the generated rules reference page of the documentations site.
+const rulesPage = "../../docs/src/content/docs/usage-guide/rules.md"
+
+func TestRulesMarkdown_UpToDate(t *testing.T) {
+	data, err := os.ReadFile(rulesPage)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", rulesPage, err)
+	}
+
+	if string(data) != RulesMarkdown() {
+		t.Errorf("%s is out of date, regenerate it with \"task docs:rules\"", rulesPage)
+	}
+}
+
+func TestRule_Explain(t *testing.T) {
+	r, _ := LookupRule("subject")
+	explanation := r.Explain()
+
+	sections := []string{"subject", "Rationale:", "Good:", "Bad:", "Options:"}
+	for _, want := range sections {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("expected explanation to contain %q, got:\n%s", want, explanation)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/fuzzy"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// gitTrailers lists the trailers commonly added to commit messages by Git and the code
+// review tools, which are not footers of the Conventional Commits specification but
+// are not misspellings of them either.
+var gitTrailers = []string{
+	"Signed-off-by",
+	"Co-authored-by",
+	"Reviewed-by",
+	"Acked-by",
+	"Tested-by",
+	"Reported-by",
+	"Suggested-by",
+	"Helped-by",
+	"Cc",
+	"See-also",
+	"Change-Id",
+}
+
+// Trailers returns the keys of the trailers commonly added to commit messages besides
+// the footers recognised by the parser, e.g. "Signed-off-by".
+func Trailers() []string {
+	return slices.Clone(gitTrailers)
+}
+
+// footerKeyPattern matches the key of a line formatted like a footer, e.g. "Closes"
+// in "Closes: #12" or "Refs #12".
+var footerKeyPattern = regexp.MustCompile(
+	`^[A-Za-z][A-Za-z-]*(?: [A-Za-z-]+)?(?:: | #)`,
+)
+
+// trailerParagraph returns the last paragraph of the body lines, where the trailers
+// are expected, or nil if the header is not followed by any other paragraph.
+func trailerParagraph(lines []parser.Line) []parser.Line {
+	end := len(lines)
+	for end > 0 && lines[end-1].Kind == parser.LineBlank {
+		end--
+	}
+
+	for idx := end - 1; idx >= 0; idx-- {
+		if lines[idx].Kind == parser.LineBlank {
+			return lines[idx+1 : end]
+		}
+	}
+	return nil
+}
+
+// checkFooterKeys reports the lines of the last paragraph formatted like footers whose
+// key is a misspelling of a known footer or trailer, e.g. "Closses: #12", which the
+// parser treats as part of the body. The misspellings are only fixed if the whole
+// paragraph is made of footers, as a line of prose (e.g. "Closed: ...") may only look
+// like one.
+func checkFooterKeys(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	known := append(parser.KnownFooters(), gitTrailers...)
+	lines := trailerParagraph(bodyLines(msg))
+	code := codeLines(lines)
+
+	// The lines indented with whitespaces continue the value of the trailer above
+	trailers := true
+	for _, line := range lines {
+		continued := strings.HasPrefix(line.Text, " ") ||
+			strings.HasPrefix(line.Text, "\t")
+		if line.Kind != parser.LineFooter && !continued &&
+			!footerKeyPattern.MatchString(line.Text) {
+			trailers = false
+		}
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, line := range lines {
+		if line.Kind == parser.LineFooter || code[line.Number] {
+			continue
+		}
+
+		loc := footerKeyPattern.FindStringIndex(line.Text)
+		if loc == nil {
+			continue
+		}
+		key := line.Text[:loc[1]-2]
+
+		suggestions := fuzzy.Suggest(key, known)
+		if len(suggestions) == 0 {
+			continue
+		}
+
+		span := parser.Span{Start: 0, End: len(key)}
+		d := Diagnostic{
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf(
+				"line %d: unknown footer %q is treated as part of the body, %s",
+				line.Number,
+				key,
+				fuzzy.DidYouMean(suggestions),
+			),
+			Line: line.Number,
+			Span: &span,
+		}
+		if trailers && len(suggestions) == 1 {
+			d.Fix = &Fix{
+				Title:   fmt.Sprintf("Change the footer to %q", suggestions[0]),
+				Line:    line.Number,
+				Span:    span,
+				NewText: suggestions[0],
+			}
+		}
+		diagnostics = append(diagnostics, d)
+	}
+
+	return diagnostics
+}
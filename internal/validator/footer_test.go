@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFooterKeys(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string // The fixed message, empty if no diagnostic is expected
+	}{
+		{"fix: handle it\n\nCloses: #12", ""},
+		{"fix: handle it\n\nCloses: #12\nSigned-off-by: Jane <jane@example.com>", ""},
+		{"fix: handle it\n\nNote: this is fine.", ""},
+		{"fix: handle it\n\nClosses: #12", "fix: handle it\n\nCloses: #12"},
+		{"fix: handle it\n\nRef #12", "fix: handle it\n\nRefs #12"},
+		{
+			"fix: handle it\n\nBREAKING CHANGES: gone",
+			"fix: handle it\n\nBREAKING CHANGE: gone",
+		},
+		{
+			"fix: handle it\n\nRefs: #1\nReviewd-by: Jane <jane@example.com>",
+			"fix: handle it\n\nRefs: #1\nReviewed-by: Jane <jane@example.com>",
+		},
+		{"fix: handle it\n\n    Closses: #12", ""},
+		{
+			"fix: handle it\n\nClosed: the old reader.\n\nRefs: #12",
+			"",
+		},
+		{
+			"fix: handle it\n\nBREAKING CHANGE: gone\n  for good\nClosses: #12",
+			"fix: handle it\n\nBREAKING CHANGE: gone\n  for good\nCloses: #12",
+		},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		d := lintRule(t, v, "footer-key", tt.message, nil)
+		if tt.want == "" {
+			if len(d) != 0 {
+				t.Errorf("%q: expected no diagnostics, got %v", tt.message, d)
+			}
+			continue
+		}
+
+		if len(d) != 1 || d[0].Fix == nil {
+			t.Fatalf("%q: expected a fixable diagnostic, got %v", tt.message, d)
+		}
+		if got := ApplyFix(tt.message, *d[0].Fix); got != tt.want {
+			t.Errorf("%q: fixed to %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestFooterKeys_Prose(t *testing.T) {
+	message := "fix: handle it\n\nThe reader used to panic.\nClosed: the old reader."
+
+	d := lintRule(t, NewValidator(), "footer-key", message, nil)
+	if len(d) != 1 {
+		t.Fatalf("expected a diagnostic, got %v", d)
+	}
+	if d[0].Fix != nil {
+		t.Errorf("expected no fix of a line of prose, got %+v", d[0].Fix)
+	}
+}
+
+func TestTypeSuggestion(t *testing.T) {
+	v := NewValidator()
+
+	d := lintRule(t, v, "type", "refactr: simplify it", nil)
+	if len(d) != 1 || !strings.HasSuffix(d[0].Message, `did you mean "refactor"?`) {
+		t.Fatalf("expected a suggestion of refactor, got %v", d)
+	}
+	if d[0].Fix == nil || d[0].Fix.NewText != "refactor" {
+		t.Errorf("expected a fix to refactor, got %+v", d[0].Fix)
+	}
+
+	// An ambiguous typo is not fixed
+	d = lintRule(t, v, "type", "fest: cover it", nil)
+	want := `did you mean "feat" or "test"?`
+	if len(d) != 1 || !strings.HasSuffix(d[0].Message, want) {
+		t.Fatalf("expected suggestions of feat and test, got %v", d)
+	}
+	if d[0].Fix != nil {
+		t.Errorf("expected no fix, got %+v", d[0].Fix)
+	}
+}
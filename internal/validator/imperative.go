@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Weburz/crisp/internal/mood"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// firstWord returns the first word of the description (skipping leading quotation
+// marks) along with its byte offset. Returns an empty word if the description starts
+// with a code span.
+func firstWord(s string) (string, int) {
+	start := subjectStart(s)
+	rest := s[start:]
+	if strings.HasPrefix(rest, "`") {
+		return "", start
+	}
+
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	return rest[:end], start
+}
+
+// checkImperativeMood reports a description whose first word is a verb in the past
+// tense, the third person or the gerund, unless the word is allowed by the
+// configuration.
+func checkImperativeMood(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	word, offset := firstWord(msg.Description)
+	for _, allowed := range v.config.Mood.Allow {
+		if strings.EqualFold(word, allowed) {
+			return nil
+		}
+	}
+
+	base, form, ok := mood.Imperative(word)
+	if !ok {
+		return nil
+	}
+
+	// Keep the case of the word, the casing is validated by the "subject" rule
+	if first, _ := utf8.DecodeRuneInString(word); unicode.IsUpper(first) {
+		base = strings.ToUpper(base[:1]) + base[1:]
+	}
+
+	described := "is in the " + string(form)
+	if form == mood.Gerund {
+		described = "is a gerund"
+	}
+
+	start := msg.Spans.Description.Start + offset
+	span := parser.Span{Start: start, End: start + len(word)}
+	diagnostics := atHeader([]Diagnostic{{
+		Severity: SeverityWarning,
+		Message: fmt.Sprintf(
+			"description should be in the imperative mood, %q %s, use %q instead",
+			word,
+			described,
+			base,
+		),
+	}}, msg, span)
+	diagnostics[0].Fix = &Fix{
+		Title:   fmt.Sprintf("Change %q to %q", word, base),
+		Line:    1,
+		Span:    span,
+		NewText: base,
+	}
+	return diagnostics
+}
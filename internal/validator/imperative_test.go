@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+)
+
+func TestImperativeMood(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string // The fixed message, empty if no diagnostic is expected
+	}{
+		{"feat: add it", ""},
+		{"feat: added it", "feat: add it"},
+		{"fix(parser): handles footers", "fix(parser): handle footers"},
+		{"refactor: moving it", "refactor: move it"},
+		{"revert: \"Added it\"", "revert: \"Add it\""},
+		{"fix: `parsed` is reset", ""},
+		{"fix: broken links", ""},
+		{"docs: logs are rotated", "docs: log are rotated"},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		d := lintRule(t, v, "imperative-mood", tt.message, nil)
+		if tt.want == "" {
+			if len(d) != 0 {
+				t.Errorf("%q: expected no diagnostics, got %v", tt.message, d)
+			}
+			continue
+		}
+
+		if len(d) != 1 || d[0].Fix == nil || d[0].Severity != SeverityWarning {
+			t.Fatalf("%q: expected a fixable warning, got %v", tt.message, d)
+		}
+		if got := ApplyFix(tt.message, *d[0].Fix); got != tt.want {
+			t.Errorf("%q: fixed to %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestImperativeMood_Allow(t *testing.T) {
+	cfg := config.Default()
+	cfg.Mood.Allow = []string{"logs"}
+
+	d := lintRule(
+		t,
+		NewValidatorWithConfig(cfg),
+		"imperative-mood",
+		"docs: logs are rotated",
+		nil,
+	)
+	if len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+}
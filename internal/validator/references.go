@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// acceptedReferences returns the issue references of the commit message which are
+// accepted by the configuration, i.e. the ones found in the footers (and in the body
+// if enabled) except the Jira-style keys of the projects which are not listed.
+func (v *validator) acceptedReferences(msg *parser.CommitMessage) []parser.Reference {
+	cfg := v.config.References
+
+	accepted := []parser.Reference{}
+	for _, ref := range msg.References {
+		if ref.Footer == "" && !cfg.Body {
+			continue
+		}
+		if ref.Kind == parser.ReferenceKey &&
+			!slices.Contains(cfg.Projects, ref.Project) {
+			continue
+		}
+		accepted = append(accepted, ref)
+	}
+	return accepted
+}
+
+// checkIssueReference reports a commit message of one of the configured types which
+// does not reference any issue.
+func checkIssueReference(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	if !slices.Contains(v.config.References.Types, msg.Type) ||
+		len(v.acceptedReferences(msg)) > 0 {
+		return nil
+	}
+
+	where := "footer"
+	if v.config.References.Body {
+		where = "footer or in the body"
+	}
+	return atHeader([]Diagnostic{{
+		Message: fmt.Sprintf(
+			"commit messages of type %q must reference an issue in a %s, "+
+				"e.g. \"Refs: #123\"",
+			msg.Type,
+			where,
+		),
+	}}, msg, msg.Spans.Type)
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+)
+
+func TestIssueReference(t *testing.T) {
+	tests := []struct {
+		message string
+		body    bool
+		want    bool // Whether a diagnostic is expected
+	}{
+		{"feat: support it", false, true},
+		{"docs: describe it", false, false},
+		{"feat: support it\n\nCloses: #12", false, false},
+		{"feat: support it\n\nFixes: octo/crisp#12", false, false},
+		{"feat: support it\n\nRefs: GH-12", false, false},
+		{"feat: support it\n\nCloses #12", false, false},
+		{"feat: support it\n\nBody.\n\nFixes #12, #13", false, false},
+		{"feat: support it\n\nRefs: PROJ-12", false, false},
+		{"feat: support it\n\nRefs: OPS-12", false, true},
+		{"feat: support it\n\nAs reported in #12.", false, true},
+		{"feat: support it\n\nAs reported in #12.", true, false},
+		{"feat: support it\n\nThe text is in UTF-8.", true, true},
+	}
+
+	for _, tt := range tests {
+		cfg := config.Default()
+		cfg.Rules["issue-reference"] = "error"
+		cfg.References.Body = tt.body
+		cfg.References.Projects = []string{"PROJ"}
+
+		v := NewValidatorWithConfig(cfg)
+		d := lintRule(t, v, "issue-reference", tt.message, nil)
+		if got := len(d) > 0; got != tt.want {
+			t.Errorf("%q (body: %t): expected a diagnostic: %t, got %v", tt.message,
+				tt.body, tt.want, d)
+		}
+	}
+}
+
+func TestIssueReference_Optional(t *testing.T) {
+	d := lintRule(t, NewValidator(), "issue-reference", "feat: support it", nil)
+	if len(d) != 0 {
+		t.Errorf("expected the rule to be disabled by default, got %v", d)
+	}
+}
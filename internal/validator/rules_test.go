@@ -0,0 +1,187 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+func TestRules_Documented(t *testing.T) {
+	seen := map[string]bool{}
+
+	for _, r := range Rules() {
+		if r.ID == "" || r.Summary == "" || r.Rationale == "" {
+			t.Errorf("rule %q is missing its ID, summary or rationale", r.ID)
+		}
+		if len(r.Good) == 0 || len(r.Bad) == 0 {
+			t.Errorf("rule %q is missing good or bad examples", r.ID)
+		}
+		if seen[r.ID] {
+			t.Errorf("rule %q is registered more than once", r.ID)
+		}
+		seen[r.ID] = true
+	}
+}
+
+func TestRules_Configurable(t *testing.T) {
+	ids := []string{}
+	for _, r := range Rules() {
+		ids = append(ids, r.ID)
+	}
+
+	if !reflect.DeepEqual(ids, config.RuleIDs) {
+		t.Errorf("expected the configurable rules %v, got %v", ids, config.RuleIDs)
+	}
+}
+
+func TestRules_Examples(t *testing.T) {
+	v := NewValidator()
+
+	for _, r := range Rules() {
+		// The examples of the rules validating other things are tested separately
+		if r.check == nil {
+			continue
+		}
+
+		for _, example := range r.Good {
+			msg, err := parser.ParseCommitMessage(example)
+			if err != nil {
+				t.Fatalf("rule %q: failed to parse %q: %v", r.ID, example, err)
+			}
+			if diagnostics := r.check(v, msg); len(diagnostics) != 0 {
+				t.Errorf("rule %q: good example %q was rejected", r.ID, example)
+			}
+		}
+
+		for _, example := range r.Bad {
+			msg, err := parser.ParseCommitMessage(example)
+			if err != nil {
+				t.Fatalf("rule %q: failed to parse %q: %v", r.ID, example, err)
+			}
+			if diagnostics := r.check(v, msg); len(diagnostics) == 0 {
+				t.Errorf("rule %q: bad example %q was accepted", r.ID, example)
+			}
+		}
+	}
+}
+
+func TestRules_Examples_Lint(t *testing.T) {
+	// The files changed by the examples of the rules checking them
+	paths := map[string][]string{
+		"scope-paths":  {"internal/parser/parser.go"},
+		"type-changes": {"internal/config/config.go"},
+	}
+
+	for _, r := range Rules() {
+		if r.check == nil && r.checkPaths == nil {
+			continue
+		}
+
+		// The examples of an optional rule are linted with the rule enabled
+		cfg := config.Default()
+		if r.Optional {
+			cfg.Rules[r.ID] = "error"
+		}
+		v := NewValidatorWithConfig(cfg)
+
+		for _, example := range r.Good {
+			msg, err := parser.ParseCommitMessage(example)
+			if err != nil {
+				t.Fatalf("rule %q: failed to parse %q: %v", r.ID, example, err)
+			}
+			if d := v.LintWithPaths(msg, paths[r.ID]); len(d) != 0 {
+				t.Errorf("rule %q: good example %q was reported: %v", r.ID, example, d)
+			}
+		}
+
+		for _, example := range r.Bad {
+			msg, err := parser.ParseCommitMessage(example)
+			if err != nil {
+				t.Fatalf("rule %q: failed to parse %q: %v", r.ID, example, err)
+			}
+			reported := false
+			for _, d := range v.LintWithPaths(msg, paths[r.ID]) {
+				reported = reported || d.Rule == r.ID
+			}
+			if !reported {
+				t.Errorf("rule %q: bad example %q was not reported", r.ID, example)
+			}
+		}
+	}
+}
+
+func TestLookupRule(t *testing.T) {
+	if _, ok := LookupRule("header-length"); !ok {
+		t.Error("expected header-length rule to exist")
+	}
+	if _, ok := LookupRule("no-such-rule"); ok {
+		t.Error("expected no-such-rule to not exist")
+	}
+}
+
+func TestLint(t *testing.T) {
+	msg := &parser.CommitMessage{Type: "Feat", Scope: "Parser", Description: "Add it."}
+
+	diagnostics := NewValidator().Lint(msg)
+
+	want := []string{"type", "scope-case", "subject"}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %d: %v", len(want), len(diagnostics),
+			diagnostics)
+	}
+	for idx, d := range diagnostics {
+		if d.Rule != want[idx] || d.Severity != SeverityError {
+			t.Errorf("diagnostic %d: expected error[%s], got %s", idx, want[idx], d)
+		}
+	}
+}
+
+func TestLintHeader(t *testing.T) {
+	cfg := config.Default()
+	cfg.Rules["issue-reference"] = "error"
+	cfg.Rules["signed-off-by"] = "error"
+	v := NewValidatorWithConfig(cfg)
+
+	// The rules needing the body or the footers do not apply to a lone header
+	msg, err := parser.ParseCommitMessage("fix(cli): handle empty input")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := v.LintHeader(msg); len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+	if d := v.Lint(msg); len(d) != 2 {
+		t.Errorf("expected 2 diagnostics linting the whole message, got %v", d)
+	}
+
+	msg, err = parser.ParseCommitMessage("Fix(cli): handle empty input ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"type", "trailing-whitespace"}
+	got := []string{}
+	for _, d := range v.LintHeader(msg) {
+		got = append(got, d.Rule)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected diagnostics of the rules %v, got %v", want, got)
+	}
+}
+
+func TestValidateMessage_ValidationError(t *testing.T) {
+	msg := &parser.CommitMessage{Type: "feet", Description: "add it"}
+
+	_, err := ValidateMessage(msg)
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected *ValidationError, got %T", err)
+	}
+	if !strings.Contains(err.Error(), "error[type]: invalid commit message type") {
+		t.Errorf("unexpected error message: %s", err)
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/glob"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// scopesOf returns the scopes covering the path. These are the configured scopes with
+// a pattern matching the path or, without any configured scopes, the names of the
+// directories of the path (which are also the names of the Go packages). The most
+// specific scope comes first.
+func (v *validator) scopesOf(file string) []string {
+	if len(v.config.Scopes) == 0 {
+		scopes := []string{}
+		for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+			scopes = append(scopes, path.Base(dir))
+		}
+		return scopes
+	}
+
+	scopes := []string{}
+	for _, scope := range slices.Sorted(maps.Keys(v.config.Scopes)) {
+		if glob.MatchAny(v.config.Scopes[scope], file) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// SuggestScope returns the scope covering the most paths, preferring the most specific
+// and then the alphabetically first scope on ties. Returns an empty string if none of
+// the paths are covered by a scope.
+func (v *validator) SuggestScope(paths []string) string {
+	counts := map[string]int{}
+	for _, file := range paths {
+		if scopes := v.scopesOf(file); len(scopes) > 0 {
+			counts[scopes[0]]++
+		}
+	}
+
+	best := ""
+	for _, scope := range slices.Sorted(maps.Keys(counts)) {
+		if counts[scope] > counts[best] {
+			best = scope
+		}
+	}
+	return best
+}
+
+// checkScopePaths reports a scope which covers none of the changed files along with
+// the scope covering most of them.
+func checkScopePaths(
+	v *validator,
+	msg *parser.CommitMessage,
+	paths []string,
+) []Diagnostic {
+	if msg.Scope == "" || len(paths) == 0 {
+		return nil
+	}
+
+	scope := strings.ToLower(msg.Scope)
+	for _, file := range paths {
+		if slices.Contains(v.scopesOf(file), scope) {
+			return nil
+		}
+	}
+
+	d := Diagnostic{
+		Severity: SeverityWarning,
+		Message: fmt.Sprintf(
+			"scope %q does not cover any of the changed files",
+			msg.Scope,
+		),
+	}
+
+	if suggestion := v.SuggestScope(paths); suggestion != "" {
+		d.Message += fmt.Sprintf(", did you mean %q?", suggestion)
+		if msg.Spans.Scope != nil {
+			d.Fix = &Fix{
+				Title:   fmt.Sprintf("Change the scope to %q", suggestion),
+				Line:    1,
+				Span:    *msg.Spans.Scope,
+				NewText: suggestion,
+			}
+		}
+	}
+
+	if msg.Spans.Scope == nil {
+		return []Diagnostic{d}
+	}
+	return atHeader([]Diagnostic{d}, msg, *msg.Spans.Scope)
+}
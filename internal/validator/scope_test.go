@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// scopePathsValidator returns a validator with the "scope-paths" rule enabled.
+func scopePathsValidator(scopes map[string][]string) *validator {
+	cfg := config.Default()
+	cfg.Rules["scope-paths"] = "warning"
+	cfg.Scopes = scopes
+	return NewValidatorWithConfig(cfg)
+}
+
+// lintRule lints the message with the paths and returns the diagnostics of the rule.
+func lintRule(
+	t *testing.T,
+	v *validator,
+	rule, message string,
+	paths []string,
+) []Diagnostic {
+	t.Helper()
+
+	msg, err := parser.ParseCommitMessage(message)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", message, err)
+	}
+
+	found := []Diagnostic{}
+	for _, d := range v.LintWithPaths(msg, paths) {
+		if d.Rule == rule {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func TestScopePaths_Derived(t *testing.T) {
+	v := scopePathsValidator(nil)
+	paths := []string{
+		"internal/parser/parser.go",
+		"internal/parser/parser_test.go",
+	}
+
+	d := lintRule(t, v, "scope-paths", "fix(parser): handle it", paths)
+	if len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+	if d := lintRule(t, v, "scope-paths", "fix: handle it", paths); len(d) != 0 {
+		t.Errorf("expected no diagnostics without a scope, got %v", d)
+	}
+
+	d = lintRule(t, v, "scope-paths", "fix(reader): handle it", paths)
+	if len(d) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", d)
+	}
+
+	want := `scope "reader" does not cover any of the changed files, ` +
+		`did you mean "parser"?`
+	if d[0].Message != want || d[0].Severity != SeverityWarning {
+		t.Errorf("unexpected diagnostic: %+v", d[0])
+	}
+	if d[0].Fix == nil || d[0].Fix.NewText != "parser" {
+		t.Errorf("expected a fix to the parser scope, got %+v", d[0].Fix)
+	}
+}
+
+func TestScopePaths_Configured(t *testing.T) {
+	v := scopePathsValidator(map[string][]string{
+		"cli":  {"cmd/**", "main.go"},
+		"docs": {"docs/**", "*.md"},
+	})
+
+	paths := []string{"cmd/root.go", "README.md", "main.go"}
+	if d := lintRule(t, v, "scope-paths", "feat(cli): add it", paths); len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+
+	d := lintRule(t, v, "scope-paths", "feat(parser): add it", paths)
+	if len(d) != 1 || d[0].Fix == nil || d[0].Fix.NewText != "cli" {
+		t.Errorf("expected a suggestion of the cli scope, got %v", d)
+	}
+}
+
+func TestScopePaths_Optional(t *testing.T) {
+	msg, _ := parser.ParseCommitMessage("fix(reader): handle it")
+	paths := []string{"internal/parser/parser.go"}
+
+	if d := NewValidator().LintWithPaths(msg, paths); len(d) != 0 {
+		t.Errorf("expected the optional rule to not run, got %v", d)
+	}
+
+	// The configured severity overrides the severity of the diagnostics
+	cfg := config.Default()
+	cfg.Rules["scope-paths"] = "error"
+	d := NewValidatorWithConfig(cfg).LintWithPaths(msg, paths)
+	if len(d) != 1 || d[0].Severity != SeverityError {
+		t.Errorf("expected an error, got %v", d)
+	}
+
+	// The paths are not known when linting without them
+	if d := NewValidatorWithConfig(cfg).Lint(msg); len(d) != 0 {
+		t.Errorf("expected no diagnostics without paths, got %v", d)
+	}
+}
+
+func TestLint_RuleOff(t *testing.T) {
+	cfg := config.Default()
+	cfg.Rules["subject"] = "off"
+
+	msg, _ := parser.ParseCommitMessage("fix: Handle it.")
+	got := []string{}
+	for _, d := range NewValidatorWithConfig(cfg).Lint(msg) {
+		got = append(got, d.Rule)
+	}
+
+	if !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("expected no diagnostics, got %v", got)
+	}
+}
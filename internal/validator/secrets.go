@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/secrets"
+)
+
+// checkSecrets reports the credentials found anywhere in the commit message, like
+// tokens and private keys. The secrets are redacted in the messages and identified by
+// their fingerprint instead, which can be allowed in the configuration. A secret
+// spanning several lines (e.g. a private key) is located on its first line.
+func checkSecrets(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	if len(msg.Lines) == 0 {
+		return nil
+	}
+
+	texts := []string{}
+	for _, line := range msg.Lines {
+		texts = append(texts, line.Text)
+	}
+	text := strings.Join(texts, "\n")
+
+	diagnostics := []Diagnostic{}
+	for _, f := range secrets.Scan(text, v.config.Secrets.Entropy) {
+		fingerprint := f.Fingerprint()
+		if slices.Contains(v.config.Secrets.Allow, fingerprint) {
+			continue
+		}
+
+		// Locate the start of the secret in its line
+		start := f.Start
+		i := 0
+		for start > len(msg.Lines[i].Text) {
+			start -= len(msg.Lines[i].Text) + 1
+			i++
+		}
+		line := msg.Lines[i]
+		end := min(start+f.End-f.Start, len(line.Text))
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Message: fmt.Sprintf(
+				"line %d: possible %s %q (fingerprint %s), remove it from the "+
+					"message and revoke it",
+				line.Number,
+				f.Description,
+				f.Redacted(),
+				fingerprint,
+			),
+			Line: line.Number,
+			Span: &parser.Span{Start: start, End: end},
+		})
+	}
+
+	return diagnostics
+}
+
+// Redact masks the secrets found in the text (except the allowed ones) like the
+// secrets rule does in its diagnostics, so that the text can be printed or logged
+// without disclosing them.
+func (v *validator) Redact(text string) string {
+	return v.redactPrefix(text, len(text))
+}
+
+// redactPrefix returns the first n bytes of the text with its secrets masked. The
+// secrets are searched for in the whole text, so that a secret cut off at n is masked
+// even though it would no longer be detected in the prefix alone.
+func (v *validator) redactPrefix(text string, n int) string {
+	prefix := text[:n]
+	findings := secrets.Scan(text, v.config.Secrets.Entropy)
+	for i := len(findings) - 1; i >= 0; i-- {
+		f := findings[i]
+		if f.Start >= n || slices.Contains(v.config.Secrets.Allow, f.Fingerprint()) {
+			continue
+		}
+		prefix = prefix[:f.Start] + f.Redacted() + prefix[min(f.End, n):]
+	}
+	return prefix
+}
@@ -0,0 +1,193 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// signOffTrailerPattern matches the trailers naming the people who took part in a
+// commit, i.e. its sign-offs and its co-authors.
+var signOffTrailerPattern = regexp.MustCompile(
+	`(?i)^(Signed-off-by|Co-authored-by):[ \t]*(.*?)\s*$`,
+)
+
+// identityPattern matches a well-formed identity, e.g. "Jane Doe <jane@example.com>".
+var identityPattern = regexp.MustCompile(
+	`^([^<>]*[^<>\s])\s+<([^<>\s@]+@[^<>\s@]+\.[^<>\s@]+)>$`,
+)
+
+// identity is the name and the e-mail address of a person.
+type identity struct {
+	name  string
+	email string
+}
+
+// parseIdentity parses an identity of the form "Name <email>". Returns false if the
+// identity is malformed.
+func parseIdentity(s string) (identity, bool) {
+	m := identityPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return identity{}, false
+	}
+	return identity{name: strings.TrimSpace(m[1]), email: m[2]}, true
+}
+
+// String renders the identity as "Name <email>".
+func (i identity) String() string {
+	return fmt.Sprintf("%s <%s>", i.name, i.email)
+}
+
+// matches reports whether both identities denote the same person, i.e. they have the
+// same name and the same e-mail address (ignoring its case).
+func (i identity) matches(other identity) bool {
+	return i.name == other.name && strings.EqualFold(i.email, other.email)
+}
+
+// SetIdentities sets the identities ("Name <email>") the sign-off must match one of,
+// e.g. the author of the commit and the configured user. The first of them is used to
+// fix a missing sign-off. The identity of the sign-off is not checked if none are set.
+func (v *validator) SetIdentities(identities []string) {
+	v.identities = []identity{}
+	for _, s := range identities {
+		id, ok := parseIdentity(s)
+		if ok && !slices.ContainsFunc(v.identities, id.matches) {
+			v.identities = append(v.identities, id)
+		}
+	}
+}
+
+// lastLine returns the last line of the message which is not blank.
+func lastLine(msg *parser.CommitMessage) parser.Line {
+	last := msg.Lines[0]
+	for _, line := range msg.Lines {
+		if strings.TrimSpace(line.Text) != "" {
+			last = line
+		}
+	}
+	return last
+}
+
+// appendSignOff returns the fix appending a sign-off by the identity after the last
+// line of the message, separated by a blank line unless it follows other trailers.
+func appendSignOff(msg *parser.CommitMessage, id identity) *Fix {
+	last := lastLine(msg)
+	text := "\nSigned-off-by: " + id.String()
+	if last.Number == 1 || !trailerPattern.MatchString(strings.TrimSpace(last.Text)) {
+		text = "\n" + text
+	}
+
+	end := len(last.Text)
+	return &Fix{
+		Title:   "Sign off as " + id.String(),
+		Line:    last.Number,
+		Span:    parser.Span{Start: end, End: end},
+		NewText: text,
+	}
+}
+
+// checkSignOff reports a commit message without a well-formed "Signed-off-by"
+// trailer, or whose sign-offs do not match any of the expected identities. The
+// co-authors must sign off as well if required by the configuration.
+func checkSignOff(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	if len(msg.Lines) == 0 {
+		return nil
+	}
+
+	lines := bodyLines(msg)
+	code := codeLines(lines)
+
+	diagnostics := []Diagnostic{}
+	signOffs, malformed := []identity{}, false
+	type coAuthor struct {
+		identity
+		line int
+	}
+	coAuthors := []coAuthor{}
+
+	for _, line := range lines {
+		m := signOffTrailerPattern.FindStringSubmatchIndex(line.Text)
+		if m == nil || code[line.Number] {
+			continue
+		}
+		key, value := line.Text[m[2]:m[3]], line.Text[m[4]:m[5]]
+
+		signOff := strings.EqualFold(key, "Signed-off-by")
+		id, ok := parseIdentity(value)
+		if !ok && !signOff && v.config.SignOff.CoAuthors == "ignore" {
+			continue
+		}
+		if !ok {
+			malformed = malformed || signOff
+			diagnostics = append(diagnostics, Diagnostic{
+				Message: fmt.Sprintf(
+					"line %d: malformed identity %q, expected \"%s: Name <email>\"",
+					line.Number,
+					value,
+					key,
+				),
+				Line: line.Number,
+				Span: &parser.Span{Start: m[4], End: m[5]},
+			})
+			continue
+		}
+
+		if signOff {
+			signOffs = append(signOffs, id)
+		} else {
+			coAuthors = append(coAuthors, coAuthor{id, line.Number})
+		}
+	}
+
+	// A missing sign-off is reported on the last line, where it belongs
+	last := lastLine(msg).Number
+	signedByIdentity := slices.ContainsFunc(signOffs, func(id identity) bool {
+		return slices.ContainsFunc(v.identities, id.matches)
+	})
+
+	switch {
+	case len(signOffs) == 0 && !malformed:
+		d := Diagnostic{
+			Message: "commit message is not signed off, add a " +
+				"\"Signed-off-by: Name <email>\" trailer",
+			Line: last,
+		}
+		if len(v.identities) > 0 {
+			d.Fix = appendSignOff(msg, v.identities[0])
+		}
+		diagnostics = append(diagnostics, d)
+	case len(signOffs) > 0 && len(v.identities) > 0 && !signedByIdentity:
+		expected := []string{}
+		for _, id := range v.identities {
+			expected = append(expected, fmt.Sprintf("%q", id.String()))
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Message: fmt.Sprintf(
+				"commit message is not signed off by the author, expected %s",
+				strings.Join(expected, " or "),
+			),
+			Line: last,
+			Fix:  appendSignOff(msg, v.identities[0]),
+		})
+	}
+
+	if v.config.SignOff.CoAuthors == "require" {
+		for _, c := range coAuthors {
+			if !slices.ContainsFunc(signOffs, c.matches) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Message: fmt.Sprintf(
+						"line %d: co-author %q has not signed off the commit",
+						c.line,
+						c.identity.String(),
+					),
+					Line: c.line,
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
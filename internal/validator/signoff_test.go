@@ -0,0 +1,182 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+)
+
+// signOffValidator returns a validator running the sign-off rule with the given
+// co-author policy and expected identities.
+func signOffValidator(coAuthors string, identities ...string) *validator {
+	cfg := config.Default()
+	cfg.Rules["signed-off-by"] = "error"
+	cfg.SignOff.CoAuthors = coAuthors
+
+	v := NewValidatorWithConfig(cfg)
+	v.SetIdentities(identities)
+	return v
+}
+
+func TestSignOff(t *testing.T) {
+	jane := "Jane Doe <jane@example.com>"
+
+	tests := []struct {
+		message    string
+		coAuthors  string
+		identities []string
+		want       []int // The lines of the expected diagnostics
+	}{
+		{"fix: handle it\n\nSigned-off-by: " + jane, "ignore", nil, []int{}},
+		{"fix: handle it", "ignore", nil, []int{1}},
+		{"fix: handle it\n\nSome body.\n", "ignore", nil, []int{3}},
+		{"fix: handle it\n\nSigned-off-by: Jane Doe", "ignore", nil, []int{3}},
+		{"fix: handle it\n\nSigned-off-by: <jane@doe.com>", "ignore", nil, []int{3}},
+		{"fix: handle it\n\n    Signed-off-by: " + jane, "ignore", nil, []int{3}},
+		{
+			"fix: handle it\n\nSigned-off-by: " + jane,
+			"ignore",
+			[]string{"Jane Doe <JANE@example.com>"},
+			[]int{},
+		},
+		{
+			"fix: handle it\n\nSigned-off-by: " + jane,
+			"ignore",
+			[]string{"John Doe <john@example.com>", jane},
+			[]int{},
+		},
+		{
+			"fix: handle it\n\nSigned-off-by: " + jane,
+			"ignore",
+			[]string{"John Doe <john@example.com>"},
+			[]int{3},
+		},
+		{
+			"fix: handle it\n\nCo-authored-by: John <john@example.com>\n" +
+				"Signed-off-by: " + jane,
+			"ignore",
+			nil,
+			[]int{},
+		},
+		{
+			"fix: handle it\n\nCo-authored-by: John <john@example.com>\n" +
+				"Signed-off-by: " + jane,
+			"require",
+			nil,
+			[]int{3},
+		},
+		{
+			"fix: handle it\n\nCo-authored-by: John <john@example.com>\n" +
+				"Signed-off-by: " + jane + "\nSigned-off-by: John <john@example.com>",
+			"require",
+			nil,
+			[]int{},
+		},
+		{
+			"fix: handle it\n\nCo-authored-by: John\nSigned-off-by: " + jane,
+			"ignore",
+			nil,
+			[]int{},
+		},
+		{
+			"fix: handle it\n\nCo-authored-by: John\nSigned-off-by: " + jane,
+			"require",
+			nil,
+			[]int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		v := signOffValidator(tt.coAuthors, tt.identities...)
+		got := lines(lintRule(t, v, "signed-off-by", tt.message, nil))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf(
+				"%q (%s, %v): expected diagnostics on lines %v, got %v",
+				tt.message,
+				tt.coAuthors,
+				tt.identities,
+				tt.want,
+				got,
+			)
+		}
+	}
+}
+
+func TestSignOff_Fix(t *testing.T) {
+	jane := "Jane Doe <jane@example.com>"
+
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"fix: handle it", "fix: handle it\n\nSigned-off-by: " + jane},
+		{"fix: handle it\n", "fix: handle it\n\nSigned-off-by: " + jane + "\n"},
+		{
+			"fix: handle it\n\nSome body.\n\n",
+			"fix: handle it\n\nSome body.\n\nSigned-off-by: " + jane + "\n\n",
+		},
+		{
+			"fix: handle it\n\nRefs: #12\n",
+			"fix: handle it\n\nRefs: #12\nSigned-off-by: " + jane + "\n",
+		},
+		{
+			"fix: handle it\n\nSigned-off-by: John Doe <john@example.com>\n",
+			"fix: handle it\n\nSigned-off-by: John Doe <john@example.com>\n" +
+				"Signed-off-by: " + jane + "\n",
+		},
+	}
+
+	v := signOffValidator("ignore", jane)
+	for _, tt := range tests {
+		d := lintRule(t, v, "signed-off-by", tt.message, nil)
+		if len(d) != 1 || d[0].Fix == nil {
+			t.Fatalf("%q: expected a fixable diagnostic, got %v", tt.message, d)
+		}
+		if got := ApplyFix(tt.message, *d[0].Fix); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.message, tt.want, got)
+		}
+	}
+}
+
+func TestFixMessage(t *testing.T) {
+	v := signOffValidator("ignore", "Jane Doe <jane@example.com>")
+
+	got, applied := v.FixMessage("Feat(Parser): Add it.\n", nil)
+	want := "feat(parser): add it\n\nSigned-off-by: Jane Doe <jane@example.com>\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	rules := []string{}
+	for _, d := range applied {
+		rules = append(rules, d.Rule)
+	}
+	wantRules := []string{"type", "scope-case", "subject", "signed-off-by"}
+	if !reflect.DeepEqual(rules, wantRules) {
+		t.Errorf("expected the fixes of the rules %v, got %v", wantRules, rules)
+	}
+
+	// Messages which can not be parsed are left as they are
+	got, applied = v.FixMessage("not a commit message", nil)
+	if got != "not a commit message" || len(applied) != 0 {
+		t.Errorf("expected the message to be unchanged, got %q", got)
+	}
+}
+
+func TestFixMessage_Errors(t *testing.T) {
+	v := signOffValidator("ignore", "Jane Doe <jane@example.com>")
+
+	// The fixes of the warnings are only suggestions and never applied
+	message := "fix(parser): handled whitelist crash"
+	want := message + "\n\nSigned-off-by: Jane Doe <jane@example.com>"
+	if got, _ := v.FixMessage(message, nil); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	// Only the fixes of the given rules are applied
+	got, applied := v.FixMessage("Fix(parser): handle the crash", nil, "signed-off-by")
+	want = "Fix(parser): handle the crash\n\nSigned-off-by: Jane Doe <jane@example.com>"
+	if got != want || len(applied) != 1 || applied[0].Rule != "signed-off-by" {
+		t.Errorf("expected only the sign-off to be fixed, got %q (%v)", got, applied)
+	}
+}
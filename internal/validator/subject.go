@@ -0,0 +1,104 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// quotes lists the quotation marks skipped at the start of the description to find
+// its first word, e.g. in `docs: "Describe the release process"`.
+const quotes = "\"'“”‘’„‚«»‹›「」『』"
+
+// periods lists the full stops a description must not end with.
+const periods = ".。．"
+
+// subjectStart returns the byte offset of the description past its leading quotation
+// marks.
+func subjectStart(s string) int {
+	return len(s) - len(strings.TrimLeft(s, quotes))
+}
+
+// leadingToken returns the leading run of letters and digits of the word.
+func leadingToken(word string) string {
+	end := strings.IndexFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if end < 0 {
+		return word
+	}
+	return word[:end]
+}
+
+// isAcronym reports whether the word starts with an acronym, i.e. at least two
+// uppercase letters not followed by a lowercase one (except a plural "s"), like
+// "API", "HTTP/2", "README.md" or "URLs".
+func isAcronym(word string) bool {
+	token := strings.TrimSuffix(leadingToken(word), "s")
+
+	letters := 0
+	for _, r := range token {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters >= 2
+}
+
+// isCapitalizationExempt reports whether the description may start with the word as
+// it is written, i.e. the word is an acronym or one of the configured exceptions
+// (e.g. "GitHub"). An exception also covers the word followed by punctuation, like
+// "GitHub's".
+func (v *validator) isCapitalizationExempt(word string) bool {
+	if isAcronym(word) {
+		return true
+	}
+
+	for _, exception := range v.config.Subject.Exceptions {
+		if rest, ok := strings.CutPrefix(word, exception); ok {
+			next, _ := utf8.DecodeRuneInString(rest)
+			if rest == "" || (!unicode.IsLetter(next) && !unicode.IsDigit(next)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// capitalizedWord returns the first word of the description if it starts with an
+// uppercase (or titlecase) letter and is not exempt. Leading quotation marks are
+// skipped, while a description starting with a code span (e.g. "`Config` ...") is
+// never considered capitalized. Letters of scripts without case are never uppercase.
+func (v *validator) capitalizedWord(s string) string {
+	s = s[subjectStart(s):]
+	if strings.HasPrefix(s, "`") {
+		return ""
+	}
+
+	word, _, _ := strings.Cut(s, " ")
+	first, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) && !unicode.IsTitle(first) {
+		return ""
+	}
+	if v.isCapitalizationExempt(word) {
+		return ""
+	}
+	return word
+}
+
+// fixSubject lowercases the first letter of the description (unless its first word
+// is exempt) and removes its trailing periods.
+func (v *validator) fixSubject(s string) string {
+	s = strings.TrimRight(s, periods)
+
+	if v.capitalizedWord(s) != "" {
+		start := subjectStart(s)
+		first, size := utf8.DecodeRuneInString(s[start:])
+		s = s[:start] + string(unicode.ToLower(first)) + s[start+size:]
+	}
+
+	return s
+}
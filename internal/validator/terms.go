@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+// termPattern returns the regular expression matching the phrase of the term ignoring
+// the case and the amount of whitespace between its words. An exact term must make up
+// the whole text, except for its surrounding whitespace and trailing periods.
+func termPattern(term config.Term) *regexp.Regexp {
+	words := []string{}
+	for _, word := range strings.Fields(term.Phrase) {
+		words = append(words, regexp.QuoteMeta(word))
+	}
+
+	pattern := strings.Join(words, `\s+`)
+	if term.Exact {
+		return regexp.MustCompile(`(?i)^\s*(` + pattern + `)\s*[` + periods + `]*$`)
+	}
+	return regexp.MustCompile(`(?i)(` + pattern + `)`)
+}
+
+// isWordChar reports whether the rune is part of a word, i.e. a letter or a digit.
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// findTerm returns the spans of the occurrences of the term in the text which are
+// not part of a longer word, e.g. "wip" is not found in "wipe".
+func findTerm(pattern *regexp.Regexp, text string) []parser.Span {
+	spans := []parser.Span{}
+	for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[2], m[3]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start > 0 && isWordChar(before)) || (end < len(text) && isWordChar(after)) {
+			continue
+		}
+		spans = append(spans, parser.Span{Start: start, End: end})
+	}
+	return spans
+}
+
+// matchCase capitalises the replacement if the matched text is capitalised.
+func matchCase(replacement, matched string) string {
+	first, _ := utf8.DecodeRuneInString(matched)
+	if !unicode.IsUpper(first) {
+		return replacement
+	}
+
+	r, size := utf8.DecodeRuneInString(replacement)
+	return string(unicode.ToUpper(r)) + replacement[size:]
+}
+
+// checkBannedTerms reports the configured words and phrases found in the description
+// or in the body (except in its code blocks), with the severity configured for each
+// of them. The replacement of a term, if any, is suggested as a fix.
+func checkBannedTerms(v *validator, msg *parser.CommitMessage) []Diagnostic {
+	type target struct {
+		what   string // The name of the text in the messages
+		line   int
+		offset int // The offset of the text in its line
+		text   string
+	}
+
+	description := []target{{
+		what:   "description",
+		line:   1,
+		offset: msg.Spans.Description.Start,
+		text:   msg.Description,
+	}}
+	body := []target{}
+	lines := bodyLines(msg)
+	code := codeLines(lines)
+	for _, line := range lines {
+		if line.Kind == parser.LineBody && !code[line.Number] {
+			body = append(body, target{
+				what: fmt.Sprintf("line %d", line.Number),
+				line: line.Number,
+				text: line.Text,
+			})
+		}
+	}
+
+	targets := map[string][]target{
+		"description": description,
+		"body":        body,
+		"both":        slices.Concat(description, body),
+	}
+
+	diagnostics := []Diagnostic{}
+	for _, term := range v.config.Terms.Banned {
+		pattern := termPattern(term)
+		for _, t := range targets[term.In] {
+			for _, span := range findTerm(pattern, t.text) {
+				matched := t.text[span.Start:span.End]
+				d := Diagnostic{
+					Severity: Severity(term.Severity),
+					Message:  fmt.Sprintf("%s must not contain %q", t.what, matched),
+				}
+				if len(msg.Lines) > 0 {
+					d.Line = t.line
+					d.Span = &parser.Span{
+						Start: t.offset + span.Start,
+						End:   t.offset + span.End,
+					}
+				}
+
+				if term.Replacement != "" {
+					replacement := matchCase(term.Replacement, matched)
+					d.Message += fmt.Sprintf(", use %q instead", replacement)
+					if d.Span != nil {
+						d.Fix = &Fix{
+							Title: fmt.Sprintf(
+								"Replace %q with %q",
+								matched,
+								replacement,
+							),
+							Line:    t.line,
+							Span:    *d.Span,
+							NewText: replacement,
+						}
+					}
+				}
+
+				diagnostics = append(diagnostics, d)
+			}
+		}
+	}
+
+	// Report the terms in the order they appear in the message
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		if a.Line != b.Line || a.Span == nil || b.Span == nil {
+			return a.Line - b.Line
+		}
+		return a.Span.Start - b.Span.Start
+	})
+	return diagnostics
+}
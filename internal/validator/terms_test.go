@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/parser"
+)
+
+func TestBannedTerms(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []int // The lines of the expected diagnostics
+	}{
+		{"feat: support an allowlist", []int{}},
+		{"feat: support a whitelist", []int{1}},
+		{"feat: support a Whitelist\n\nThe WHITELIST is empty.", []int{1, 3}},
+		{"feat: support whitelists", []int{}},
+		{"fix: wipe the cache", []int{}},
+		{"fix: wip", []int{1}},
+		{"fix: misc  fixes", []int{1}},
+		{"fix: handle it\n\nThis is wip.", []int{}},
+		{"docs: update", []int{1}},
+		{"docs: Update.", []int{1}},
+		{"docs: update the guide", []int{}},
+		{"fix: handle it\n\nThe slave is promoted.", []int{3}},
+		{"fix: handle it\n\n    slave.promote()", []int{}},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		got := lines(lintRule(t, v, "banned-terms", tt.message, nil))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected diagnostics on lines %v, got %v", tt.message,
+				tt.want, got)
+		}
+	}
+}
+
+func TestBannedTerms_Fix(t *testing.T) {
+	message := "feat: support the Whitelist and the blacklist"
+
+	d := lintRule(t, NewValidator(), "banned-terms", message, nil)
+	if len(d) != 2 || d[0].Fix == nil || d[1].Fix == nil {
+		t.Fatalf("expected 2 fixable diagnostics, got %v", d)
+	}
+
+	fixed := ApplyFix(ApplyFix(message, *d[1].Fix), *d[0].Fix)
+	if want := "feat: support the Allowlist and the denylist"; fixed != want {
+		t.Errorf("expected %q, got %q", want, fixed)
+	}
+}
+
+func TestBannedTerms_Config(t *testing.T) {
+	cfg := config.Default()
+	cfg.Terms.Banned = []config.Term{
+		{Phrase: "master", Replacement: "main", Severity: "error", In: "body"},
+	}
+	v := NewValidatorWithConfig(cfg)
+
+	if d := lintRule(t, v, "banned-terms", "fix: master it", nil); len(d) != 0 {
+		t.Errorf("expected the description to be skipped, got %v", d)
+	}
+
+	d := lintRule(t, v, "banned-terms", "fix: handle it\n\nRebase on master.", nil)
+	if len(d) != 1 || d[0].Severity != SeverityError {
+		t.Fatalf("expected an error, got %v", d)
+	}
+	want := `line 3 must not contain "master", use "main" instead`
+	if d[0].Message != want {
+		t.Errorf("expected %q, got %q", want, d[0].Message)
+	}
+}
+
+func TestBannedTerms_Comments(t *testing.T) {
+	cfg := config.Default()
+	cfg.Terms.Banned = append(cfg.Terms.Banned, config.Term{
+		Phrase:   "master",
+		Severity: "warning",
+		In:       "both",
+	})
+	v := NewValidatorWithConfig(cfg)
+
+	// Neither the comments of Git nor the diff below the scissors line are part of
+	// the message
+	message := "fix: reject empty lists\n\n" +
+		"# On branch master\n" +
+		"# Changes to be committed:\n" +
+		"#\tmodified:   internal/config/config.go\n" +
+		"#\n" +
+		"# ------------------------ >8 ------------------------\n" +
+		"diff --git a/internal/config/config.go b/internal/config/config.go\n" +
+		"-// The whitelist must not be empty\n"
+
+	if d := lintRule(t, v, "banned-terms", message, nil); len(d) != 2 {
+		t.Fatalf("expected the unstripped message to be reported twice, got %v", d)
+	}
+	message = parser.StripComments(message)
+	if d := lintRule(t, v, "banned-terms", message, nil); len(d) != 0 {
+		t.Errorf("expected no diagnostics, got %v", d)
+	}
+}
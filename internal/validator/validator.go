@@ -7,17 +7,50 @@
 	"fmt"
 	"slices"
 	"strings"
-	"unicode"
+	"unicode/utf8"
 
+	"github.com/Weburz/crisp/internal/config"
+	"github.com/Weburz/crisp/internal/fuzzy"
 	"github.com/Weburz/crisp/internal/parser"
+	"github.com/Weburz/crisp/internal/textwidth"
 )
 
-type validator struct{}
+type validator struct {
+	config *config.Config
+
+	// identities lists the identities the sign-off must match one of, if any
+	identities []identity
+}
+
+// validTypes lists the allowed Conventional Commit types.
+var validTypes = []string{
+	"build",
+	"ci",
+	"docs",
+	"feat",
+	"fix",
+	"perf",
+	"refactor",
+	"style",
+	"test",
+	"chore",
+}
+
+// Types returns the allowed Conventional Commit types.
+func Types() []string {
+	return slices.Clone(validTypes)
+}
 
 // The NewValidator() constructor creates and returns an instance of the validator
-// struct
+// struct using the default configuration
 func NewValidator() *validator {
-	return &validator{}
+	return NewValidatorWithConfig(config.Default())
+}
+
+// The NewValidatorWithConfig() constructor creates and returns an instance of the
+// validator struct using the given configuration
+func NewValidatorWithConfig(cfg *config.Config) *validator {
+	return &validator{config: cfg}
 }
 
 // The isValidType() method validates the type of the commit message.
@@ -27,21 +60,11 @@ func NewValidator() *validator {
 //
 // Reference: https://github.com/angular/angular/blob/22b96b9/CONTRIBUTING.md#type
 func (v *validator) isValidType(s string) error {
-	validTypes := []string{
-		"build",
-		"ci",
-		"docs",
-		"feat",
-		"fix",
-		"perf",
-		"refactor",
-		"style",
-		"test",
-		"chore",
-	}
-
 	normalized := strings.ToLower(s)
 	if !slices.Contains(validTypes, normalized) {
+		if hint := fuzzy.DidYouMean(fuzzy.Suggest(s, validTypes)); hint != "" {
+			return fmt.Errorf("invalid commit message type: %s, %s", s, hint)
+		}
 		return fmt.Errorf("invalid commit message type: %s", s)
 	}
 
@@ -76,81 +99,236 @@ func (v *validator) isValidScope(s string) error {
 
 // isValidSubject() validates the subject of the commit message.
 //
-// The subject must start with a lowercase letter and must not end with a period.
-// Additionally, the subject is compulsory and it will throw an error if not provided.
+// The subject must start with a lowercase letter and must not end with a period. The
+// subject may start with an acronym, a configured exception or a code span though,
+// and leading quotation marks are skipped. Additionally, the subject is compulsory and
+// it will throw an error if not provided.
 func (v *validator) isValidSubject(s string) error {
 	if len(s) == 0 {
 		return errors.New("commit message subject is empty")
 	}
 
-	if unicode.IsUpper(rune((s)[0])) || rune((s)[len((s))-1]) == '.' {
+	word := v.capitalizedWord(s)
+	last, _ := utf8.DecodeLastRuneInString(s)
+	period := strings.ContainsRune(periods, last)
+
+	switch {
+	case word != "" && period:
 		return fmt.Errorf(
-			"commit message subject should be lowercased & not end with a period(.)",
+			"commit message subject should be lowercased & not end with a period(.), "+
+				"%q starts with an uppercase letter",
+			word,
 		)
+	case word != "":
+		return fmt.Errorf(
+			"commit message subject should be lowercased, %q starts with an "+
+				"uppercase letter",
+			word,
+		)
+	case period:
+		return errors.New("commit message subject should not end with a period(.)")
 	}
 
 	return nil
 }
 
-// isValidLength checks whether the length of the given commit message string does not
-// exceed the predefined character limit.
-//
-// It enforces a hard limit of 50 characters, as recommended by the Conventional Commits
-// specification for concise subject lines.
+// isValidLength checks whether the length of the given string does not exceed the
+// limit. The length is measured in the unit configured for the header, i.e. in
+// terminal columns or in user-perceived characters rather than in bytes.
 //
 // Parameters:
-//   - s: The commit message string to validate.
+//   - what: The name of the validated part of the commit message, e.g. "header".
+//   - s: The string to validate.
+//   - limit: The maximum length of the string.
 //
 // Returns:
-//   - An error if the message exceeds the 50-character limit.
-//   - nil if the message length is within the allowed limit.
-func (v *validator) isValidLength(s string) error {
-	charLimit := 50
-	if len(s) > charLimit {
-		return fmt.Errorf(
-			"commit message is %d long but expected length should be %d",
-			len(s),
-			charLimit,
-		)
+//   - An error showing where the string is cut off if it exceeds the limit, with the
+//     secrets it contains redacted.
+//   - nil if the length of the string is within the limit.
+func (v *validator) isValidLength(what, s string, limit int) error {
+	unit := v.config.Header.Unit
+	length := textwidth.Measure(s, unit)
+	if length <= limit {
+		return nil
 	}
-	return nil
+
+	name := "columns"
+	if unit == textwidth.Graphemes {
+		name = "characters"
+	}
+	return fmt.Errorf(
+		"%s is %d %s long but the limit is %d, it is cut off after %q",
+		what,
+		length,
+		name,
+		limit,
+		v.redactPrefix(s, textwidth.Truncate(s, unit, limit)),
+	)
 }
 
-// ValidateMessage() validates a Conventional Commit message.
-//
-// It checks the type, scope and subject of the committ message for validation errors.
-// If any of the validation fails, then throw an error or retturn a message signifying
-// a successfull validation.
-func ValidateMessage(s *parser.CommitMessage) (string, error) {
-	v := NewValidator()
+// Lint runs all the rules against the commit message and returns the diagnostics for
+// every violation found, in the order the rules are run. The rules which need the
+// changed files of the commit are skipped.
+func (v *validator) Lint(msg *parser.CommitMessage) []Diagnostic {
+	return v.lint(msg, nil)
+}
+
+// LintWithPaths is like Lint but also runs the rules which compare the commit message
+// against the paths of the files changed by the commit. These rules are skipped if
+// paths is nil, i.e. the changed files are not known.
+func (v *validator) LintWithPaths(
+	msg *parser.CommitMessage,
+	paths []string,
+) []Diagnostic {
+	return v.lint(msg, paths)
+}
 
-	// Validate the commit message length (should not be more than 50 characters long)
-	var fullMessage string
-	if s.Scope != "" {
-		fullMessage = fmt.Sprintf("%s(%s): %s", s.Type, s.Scope, s.Description)
-	} else {
-		fullMessage = fmt.Sprintf("%s: %s", s.Type, s.Description)
+// LintHeader is like Lint but only runs the rules checking the header, for a lone
+// header like the title of a pull request which can have neither a body nor footers.
+func (v *validator) LintHeader(msg *parser.CommitMessage) []Diagnostic {
+	header := []Rule{}
+	for _, r := range rules {
+		if r.Header {
+			header = append(header, r)
+		}
 	}
-	fullMessage = strings.TrimSpace(fullMessage)
+	return v.lintRules(header, msg, nil)
+}
+
+// lint runs the enabled rules against the commit message, and the rules needing the
+// changed files if paths is not nil.
+func (v *validator) lint(msg *parser.CommitMessage, paths []string) []Diagnostic {
+	return v.lintRules(rules, msg, paths)
+}
 
-	// Validate the full commit message length
-	if err := v.isValidLength(fullMessage); err != nil {
-		return "", err
+// lintRules runs the given rules like lint does. The severity configured for a rule
+// overrides the severity of its diagnostics, and the secrets quoted by their messages
+// are redacted.
+func (v *validator) lintRules(
+	ruleset []Rule,
+	msg *parser.CommitMessage,
+	paths []string,
+) []Diagnostic {
+	diagnostics := []Diagnostic{}
+
+	for _, r := range ruleset {
+		severity, configured := v.config.Rules[r.ID]
+		if severity == "off" || (r.Optional && !configured) {
+			continue
+		}
+
+		found := []Diagnostic{}
+		if r.check != nil {
+			found = append(found, r.check(v, msg)...)
+		}
+		if r.checkPaths != nil && paths != nil {
+			found = append(found, r.checkPaths(v, msg, paths)...)
+		}
+
+		for _, d := range found {
+			d.Rule = r.ID
+			d.Message = v.Redact(d.Message)
+			if d.Fix != nil {
+				d.Fix.Title = v.Redact(d.Fix.Title)
+			}
+			if configured {
+				d.Severity = Severity(severity)
+			} else if d.Severity == "" {
+				d.Severity = SeverityError
+			}
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	return diagnostics
+}
+
+// maxFixes bounds the number of fixes applied to a commit message, in case fixes keep
+// undoing each other.
+const maxFixes = 100
+
+// FixMessage applies the fixes of the errors reported for the commit message one at a
+// time, linting the message again after each of them, and returns the fixed message
+// along with the diagnostics which were fixed. The fixes of the warnings are never
+// applied since they are mere suggestions (e.g. a similar scope), and only the fixes
+// of the given rules are applied if any are given. The rules needing the changed
+// files are run if paths is not nil. The message is returned as is if it can not be
+// parsed.
+func (v *validator) FixMessage(
+	message string,
+	paths []string,
+	only ...string,
+) (string, []Diagnostic) {
+	applied := []Diagnostic{}
+
+	for range maxFixes {
+		msg, err := parser.ParseCommitMessage(message)
+		if err != nil {
+			return message, applied
+		}
+
+		fixed := message
+		for _, d := range v.lint(msg, paths) {
+			if d.Fix == nil || d.Severity != SeverityError ||
+				(len(only) > 0 && !slices.Contains(only, d.Rule)) {
+				continue
+			}
+			if fixed = ApplyFix(message, *d.Fix); fixed != message {
+				applied = append(applied, d)
+				break
+			}
+		}
+		if fixed == message {
+			return message, applied
+		}
+		message = fixed
 	}
 
-	// Validate the commit message type and return an appropriate message else an error
-	if err := v.isValidType(s.Type); err != nil {
-		return "", fmt.Errorf("%w", err)
+	return message, applied
+}
+
+// ValidationError is returned when a commit message violates one or more rules. It
+// holds all the diagnostics reported for the message.
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+// Error renders each of the diagnostics on a line of its own followed by a hint on how
+// to learn more about the violated rules.
+func (e *ValidationError) Error() string {
+	lines := []string{}
+	for _, d := range e.Diagnostics {
+		lines = append(lines, d.String())
 	}
+	lines = append(
+		lines,
+		"",
+		"info: run \"crisp explain <RULE>\" to learn more about a rule",
+	)
+
+	return strings.Join(lines, "\n")
+}
 
-	// Validate the commit message scope
-	if err := v.isValidScope(s.Scope); err != nil {
-		return "", fmt.Errorf("%s", err)
+// HasErrors reports whether any of the diagnostics has the "error" severity.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
 	}
+	return false
+}
+
+// ValidateMessage() validates a Conventional Commit message.
+//
+// It runs all the rules against the commit message. If any of the rules report an
+// error, then a *ValidationError holding all the diagnostics is returned or else a
+// message signifying a successful validation.
+func ValidateMessage(s *parser.CommitMessage) (string, error) {
+	v := NewValidator()
 
-	// Validate the commit message subject
-	if err := v.isValidSubject(s.Description); err != nil {
-		return "", fmt.Errorf("%s", err)
+	if diagnostics := v.Lint(s); HasErrors(diagnostics) {
+		return "", &ValidationError{Diagnostics: diagnostics}
 	}
 
 	// Return a success message if the commit message validation was successful